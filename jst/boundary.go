@@ -0,0 +1,102 @@
+package jst
+
+import "time"
+
+// DefaultFiscalYearStart は、日本の官公庁や多くの企業で使われる年度の開始月です。
+const DefaultFiscalYearStart = time.April
+
+// StartOfDay は、t の JST の日付の 0 時を返します。
+func StartOfDay(t time.Time) time.Time {
+	t = From(t)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, Location())
+}
+
+// EndOfDay は、t の JST の日付の最後の時刻 (23:59:59.999999999) を返します。
+// 範囲で検索する場合は、終端を含まない DayRange の方が境界の取りこぼしがありません。
+func EndOfDay(t time.Time) time.Time {
+	return DayRange(t).End.Add(-time.Nanosecond)
+}
+
+// StartOfWeek は、t を含む週の初日の 0 時を JST で返します。weekStart は週の始まりの曜日で、
+// 月曜始まりなら time.Monday、日曜始まりなら time.Sunday を指定します。
+func StartOfWeek(t time.Time, weekStart time.Weekday) time.Time {
+	day := StartOfDay(t)
+	offset := (int(day.Weekday()) - int(weekStart) + 7) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// StartOfMonth は、t の JST の月の 1 日の 0 時を返します。
+func StartOfMonth(t time.Time) time.Time {
+	t = From(t)
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, Location())
+}
+
+// EndOfMonth は、t の JST の月の最後の時刻 (末日の 23:59:59.999999999) を返します。
+func EndOfMonth(t time.Time) time.Time {
+	return MonthRange(t).End.Add(-time.Nanosecond)
+}
+
+// FiscalYear は、t の JST の日付が属する年度を、開始月の属する西暦年で返します。
+// 例えば startMonth が 4 月なら、2026 年 3 月 31 日は 2025 年度です。
+func FiscalYear(t time.Time, startMonth time.Month) int {
+	t = From(t)
+	if t.Month() < startMonth {
+		return t.Year() - 1
+	}
+	return t.Year()
+}
+
+// StartOfFiscalYear は、t を含む年度の初日の 0 時を JST で返します。
+// startMonth は年度の開始月で、通常は DefaultFiscalYearStart を指定します。
+func StartOfFiscalYear(t time.Time, startMonth time.Month) time.Time {
+	return time.Date(FiscalYear(t, startMonth), startMonth, 1, 0, 0, 0, 0, Location())
+}
+
+// Range は [Start, End) の半開区間です。Start を含み、End を含みません。
+//
+// 集計やデータベースの検索では、EndOfDay のような最後の時刻で閉区間を作るより、
+// 次の区間の開始を End にした半開区間の方が、精度の違いによる取りこぼしがありません。
+type Range struct {
+	Start time.Time
+	End   time.Time
+}
+
+// DayRange は、t の JST の日付 1 日分の区間を返します。
+func DayRange(t time.Time) Range {
+	start := StartOfDay(t)
+	return Range{Start: start, End: start.AddDate(0, 0, 1)}
+}
+
+// WeekRange は、weekStart から始まる、t を含む 1 週間の区間を返します。
+func WeekRange(t time.Time, weekStart time.Weekday) Range {
+	start := StartOfWeek(t, weekStart)
+	return Range{Start: start, End: start.AddDate(0, 0, 7)}
+}
+
+// MonthRange は、t の JST の月 1 か月分の区間を返します。
+func MonthRange(t time.Time) Range {
+	start := StartOfMonth(t)
+	return Range{Start: start, End: start.AddDate(0, 1, 0)}
+}
+
+// FiscalYearRange は、startMonth から始まる、t を含む年度の区間を返します。
+func FiscalYearRange(t time.Time, startMonth time.Month) Range {
+	start := StartOfFiscalYear(t, startMonth)
+	return Range{Start: start, End: start.AddDate(1, 0, 0)}
+}
+
+// Contains は t が区間に含まれるかを返します。
+func (r Range) Contains(t time.Time) bool {
+	return !t.Before(r.Start) && t.Before(r.End)
+}
+
+// Duration は区間の長さを返します。
+func (r Range) Duration() time.Duration {
+	return r.End.Sub(r.Start)
+}
+
+// UTC は、Start と End を UTC に変換した区間を返します。
+// 永続化している UTC の時刻に対する検索条件に使います。
+func (r Range) UTC() Range {
+	return Range{Start: r.Start.UTC(), End: r.End.UTC()}
+}
@@ -0,0 +1,78 @@
+package jst_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shouni/go-utils/jst"
+)
+
+// justAfterMidnightUTC は JST で 2026-03-01 (日) 08:59、UTC では 2026-02-28 23:59 の時刻です。
+// UTC と JST で日付も月も異なるため、境界の計算を JST で行っているかを確かめられます。
+var justAfterMidnightUTC = time.Date(2026, time.February, 28, 23, 59, 0, 0, time.UTC)
+
+func TestDayBoundaries(t *testing.T) {
+	in := justAfterMidnightUTC
+	tests := []struct {
+		name string
+		got  time.Time
+		want time.Time
+	}{
+		{"StartOfDay", jst.StartOfDay(in), date(2026, time.March, 1, 0)},
+		{"EndOfDay", jst.EndOfDay(in), date(2026, time.March, 2, 0).Add(-time.Nanosecond)},
+		{"StartOfWeek(月曜)", jst.StartOfWeek(in, time.Monday), date(2026, time.February, 23, 0)},
+		{"StartOfWeek(日曜)", jst.StartOfWeek(in, time.Sunday), date(2026, time.March, 1, 0)},
+		{"StartOfMonth", jst.StartOfMonth(in), date(2026, time.March, 1, 0)},
+		{"EndOfMonth", jst.EndOfMonth(in), date(2026, time.April, 1, 0).Add(-time.Nanosecond)},
+		{"EndOfMonth(閏年の2月)", jst.EndOfMonth(date(2028, time.February, 10, 0)), date(2028, time.March, 1, 0).Add(-time.Nanosecond)},
+		{"StartOfFiscalYear(4月)", jst.StartOfFiscalYear(in, jst.DefaultFiscalYearStart), date(2025, time.April, 1, 0)},
+		{"StartOfFiscalYear(4月当日)", jst.StartOfFiscalYear(date(2026, time.April, 1, 0), time.April), date(2026, time.April, 1, 0)},
+		{"StartOfFiscalYear(1月)", jst.StartOfFiscalYear(in, time.January), date(2026, time.January, 1, 0)},
+		{"StartOfFiscalYear(10月)", jst.StartOfFiscalYear(in, time.October), date(2025, time.October, 1, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.got.Equal(tt.want) || tt.got.Location() != jst.Location() {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+
+	if got := jst.FiscalYear(in, jst.DefaultFiscalYearStart); got != 2025 {
+		t.Errorf("FiscalYear() = %d, want 2025", got)
+	}
+}
+
+func TestRange(t *testing.T) {
+	day := jst.DayRange(justAfterMidnightUTC)
+	if !day.Contains(justAfterMidnightUTC) || day.Contains(day.End) || !day.Contains(day.Start) {
+		t.Errorf("DayRange() = %v は半開区間になっていない", day)
+	}
+	if day.Duration() != 24*time.Hour {
+		t.Errorf("Duration() = %v, want 24h", day.Duration())
+	}
+
+	// UTC に変換すると、JST の 0 時は前日の 15:00 になる。
+	utc := day.UTC()
+	if want := time.Date(2026, time.February, 28, 15, 0, 0, 0, time.UTC); utc.Start != want || utc.Start.Location() != time.UTC {
+		t.Errorf("UTC().Start = %v, want %v", utc.Start, want)
+	}
+	if want := time.Date(2026, time.March, 1, 15, 0, 0, 0, time.UTC); utc.End != want {
+		t.Errorf("UTC().End = %v, want %v", utc.End, want)
+	}
+
+	tests := []struct {
+		name string
+		r    jst.Range
+		want time.Duration
+	}{
+		{"WeekRange", jst.WeekRange(justAfterMidnightUTC, time.Monday), 7 * 24 * time.Hour},
+		{"MonthRange", jst.MonthRange(justAfterMidnightUTC), 31 * 24 * time.Hour},
+		{"FiscalYearRange", jst.FiscalYearRange(justAfterMidnightUTC, time.April), 365 * 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got := tt.r.Duration(); got != tt.want || !tt.r.Contains(justAfterMidnightUTC) {
+			t.Errorf("%s = %v, Duration() = %v, want %v", tt.name, tt.r, got, tt.want)
+		}
+	}
+}
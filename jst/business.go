@@ -0,0 +1,172 @@
+package jst
+
+import (
+	"slices"
+	"time"
+)
+
+// maxClosedRun は、営業日を探すときに連続して休業日を読み飛ばす上限の日数です。
+// すべての曜日を休業日にしたような設定で無限に探し続けないためのものです。
+const maxClosedRun = 366 * 10
+
+// Closure は祝日以外の休業日を判定する関数です。date は JST の 0 時で渡されます。
+type Closure func(date time.Time) bool
+
+// YearEndClosure は 12 月 29 日から 1 月 3 日までの年末年始の休業です。
+var YearEndClosure = AnnualClosure(time.December, 29, time.January, 3)
+
+// AnnualClosure は、毎年 start から end まで（両端を含む）を休業日とする Closure を返します。
+// end が start より前の日付なら、年をまたぐ期間とみなします。
+func AnnualClosure(startMonth time.Month, startDay int, endMonth time.Month, endDay int) Closure {
+	start, end := int(startMonth)*100+startDay, int(endMonth)*100+endDay
+	return func(date time.Time) bool {
+		d := int(date.Month())*100 + date.Day()
+		if start <= end {
+			return start <= d && d <= end
+		}
+		return d >= start || d <= end
+	}
+}
+
+// ClosedOn は、指定した日付を休業日とする Closure を返します。創立記念日などの会社独自の休日に使います。
+// 日付は JST に変換したうえで、時刻を無視して比べます。
+func ClosedOn(dates ...time.Time) Closure {
+	type civil struct {
+		year  int
+		month time.Month
+		day   int
+	}
+	closed := make(map[civil]struct{}, len(dates))
+	for _, d := range dates {
+		d = From(d)
+		closed[civil{d.Year(), d.Month(), d.Day()}] = struct{}{}
+	}
+	return func(date time.Time) bool {
+		_, ok := closed[civil{date.Year(), date.Month(), date.Day()}]
+		return ok
+	}
+}
+
+// Calendar は営業日の定義です。ゼロ値は、土日と祝日を休業日とする暦です。
+//
+// 営業日の計算は、入力のロケーションによらず JST の日付で行います。
+type Calendar struct {
+	// Weekends は休業する曜日です。nil なら土曜日と日曜日、空のスライスなら曜日では休業しません。
+	Weekends []time.Weekday
+
+	// OpenOnHolidays が true なら、祝日（振替休日・国民の休日を含む）も営業日とします。
+	OpenOnHolidays bool
+
+	// Closures は祝日以外の休業日です。いずれかが true を返す日は休業日です。
+	Closures []Closure
+}
+
+// defaultWeekends は Calendar.Weekends が nil のときの休業する曜日です。
+var defaultWeekends = []time.Weekday{time.Saturday, time.Sunday}
+
+// defaultCalendar は、パッケージ関数が使う土日と祝日を休業日とする暦です。
+var defaultCalendar = &Calendar{}
+
+// IsBusinessDay は、t の JST の日付が土日でも祝日でもないかを返します。
+func IsBusinessDay(t time.Time) bool {
+	return defaultCalendar.IsBusinessDay(t)
+}
+
+// AddBusinessDays は、土日と祝日を除いて n 営業日後の時刻を返します。詳しくは Calendar.AddBusinessDays を参照してください。
+func AddBusinessDays(t time.Time, n int) time.Time {
+	return defaultCalendar.AddBusinessDays(t, n)
+}
+
+// NextBusinessDay は、土日と祝日を除いた翌営業日の同じ時刻を返します。
+func NextBusinessDay(t time.Time) time.Time {
+	return defaultCalendar.NextBusinessDay(t)
+}
+
+// BusinessDaysBetween は、土日と祝日を除いて a から b までの営業日数を返します。
+// 詳しくは Calendar.BusinessDaysBetween を参照してください。
+func BusinessDaysBetween(a, b time.Time) int {
+	return defaultCalendar.BusinessDaysBetween(a, b)
+}
+
+// IsBusinessDay は、t の JST の日付が営業日かを返します。
+func (c *Calendar) IsBusinessDay(t time.Time) bool {
+	return c.isOpen(StartOfDay(t))
+}
+
+// AddBusinessDays は、t の JST の日付から n 営業日進めた日の、t と同じ時刻を JST で返します。
+// n が負なら遡ります。n が 0 なら t を JST に変換して返します。
+//
+// 起点の日は数えないため、t が休業日でも、その後の最初の営業日が 1 営業日後です。
+// すべての曜日を休業日にした暦のように、10 年分探しても営業日が無ければゼロ値を返します。
+func (c *Calendar) AddBusinessDays(t time.Time, n int) time.Time {
+	t = From(t)
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	date := StartOfDay(t)
+	for range n {
+		var ok bool
+		if date, ok = c.seek(date, step); !ok {
+			return time.Time{}
+		}
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), Location())
+}
+
+// NextBusinessDay は、t の翌営業日の同じ時刻を JST で返します。AddBusinessDays(t, 1) と同じで、
+// 営業日が無い暦ではゼロ値を返します。
+func (c *Calendar) NextBusinessDay(t time.Time) time.Time {
+	return c.AddBusinessDays(t, 1)
+}
+
+// BusinessDaysBetween は、a の JST の日付の翌日から b の JST の日付までに含まれる営業日数を返します。
+// b が営業日なら、AddBusinessDays(a, n) が b の日付になる n と一致します。b が a より前なら負の値です。
+func (c *Calendar) BusinessDaysBetween(a, b time.Time) int {
+	from, to := StartOfDay(a), StartOfDay(b)
+	sign := 1
+	if to.Before(from) {
+		// 遡る場合は、b の日付から a の前日までを数えます。
+		from, to = to.AddDate(0, 0, -1), from.AddDate(0, 0, -1)
+		sign = -1
+	}
+	n := 0
+	for d := from.AddDate(0, 0, 1); !d.After(to); d = d.AddDate(0, 0, 1) {
+		if c.isOpen(d) {
+			n++
+		}
+	}
+	return sign * n
+}
+
+// seek は date から step 方向にある最初の営業日を返します。maxClosedRun 日探しても
+// 見つからなければ false を返します。
+func (c *Calendar) seek(date time.Time, step int) (time.Time, bool) {
+	for range maxClosedRun {
+		date = date.AddDate(0, 0, step)
+		if c.isOpen(date) {
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// isOpen は、JST の 0 時で表した date が営業日かを返します。
+func (c *Calendar) isOpen(date time.Time) bool {
+	weekends := c.Weekends
+	if weekends == nil {
+		weekends = defaultWeekends
+	}
+	if slices.Contains(weekends, date.Weekday()) {
+		return false
+	}
+	if !c.OpenOnHolidays && IsHoliday(date) {
+		return false
+	}
+	for _, closed := range c.Closures {
+		if closed(date) {
+			return false
+		}
+	}
+	return true
+}
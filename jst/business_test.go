@@ -0,0 +1,136 @@
+package jst_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shouni/go-utils/jst"
+)
+
+// date は JST の日付と時刻を作ります。
+func date(year int, month time.Month, day, hour int) time.Time {
+	return time.Date(year, month, day, hour, 0, 0, 0, jst.Location())
+}
+
+func TestAddBusinessDays(t *testing.T) {
+	tests := []struct {
+		name string
+		from time.Time
+		n    int
+		want time.Time
+	}{
+		{"平日から翌日", date(2025, time.March, 3, 10), 1, date(2025, time.March, 4, 10)},
+		{"金曜日から土日を飛ばす", date(2025, time.March, 7, 10), 1, date(2025, time.March, 10, 10)},
+		{"春分の日を飛ばす", date(2025, time.March, 19, 10), 1, date(2025, time.March, 21, 10)},
+		{"大型連休をまたぐ", date(2025, time.May, 2, 18), 1, date(2025, time.May, 7, 18)},
+		{"休業日の起点は数えない", date(2025, time.March, 8, 9), 1, date(2025, time.March, 10, 9)},
+		{"遡る", date(2025, time.May, 7, 9), -2, date(2025, time.May, 1, 9)},
+		{"0 はそのまま", date(2025, time.March, 8, 9), 0, date(2025, time.March, 8, 9)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jst.AddBusinessDays(tt.from, tt.n); !got.Equal(tt.want) {
+				t.Errorf("AddBusinessDays(%v, %d) = %v, want %v", tt.from, tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestBusinessDaysUseJSTDate は、入力のロケーションによらず JST の日付で判定することを確認します。
+func TestBusinessDaysUseJSTDate(t *testing.T) {
+	// 2025-03-07T16:00Z は JST で土曜日 01:00。
+	utc := time.Date(2025, time.March, 7, 16, 0, 0, 0, time.UTC)
+	if jst.IsBusinessDay(utc) {
+		t.Error("JST で土曜日になる時刻が営業日と判定された")
+	}
+	got := jst.NextBusinessDay(utc)
+	if want := date(2025, time.March, 10, 1); !got.Equal(want) || got.Location() != jst.Location() {
+		t.Errorf("NextBusinessDay() = %v, want %v", got, want)
+	}
+}
+
+func TestBusinessDaysBetween(t *testing.T) {
+	tests := []struct {
+		a, b time.Time
+		want int
+	}{
+		{date(2025, time.May, 1, 0), date(2025, time.May, 7, 0), 2},
+		{date(2025, time.May, 7, 0), date(2025, time.May, 1, 0), -2},
+		{date(2025, time.March, 3, 0), date(2025, time.March, 3, 23), 0},
+		{date(2025, time.March, 1, 0), date(2025, time.March, 31, 0), 20},
+	}
+	for _, tt := range tests {
+		if got := jst.BusinessDaysBetween(tt.a, tt.b); got != tt.want {
+			t.Errorf("BusinessDaysBetween(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		// 行き先が営業日なら AddBusinessDays と対応すること。
+		if jst.IsBusinessDay(tt.b) && tt.want != 0 {
+			if got := jst.AddBusinessDays(tt.a, tt.want); dateOf(got) != dateOf(tt.b) {
+				t.Errorf("AddBusinessDays(%v, %d) = %v, want %v の日付", tt.a, tt.want, got, tt.b)
+			}
+		}
+	}
+}
+
+// dateOf は t の JST の日付を文字列で返します。
+func dateOf(t time.Time) string {
+	return t.In(jst.Location()).Format(time.DateOnly)
+}
+
+func TestCalendarClosures(t *testing.T) {
+	founded := date(2025, time.January, 6, 0)
+	cal := &jst.Calendar{Closures: []jst.Closure{jst.YearEndClosure, jst.ClosedOn(founded)}}
+
+	// 12/27 (金) の翌営業日は、年末年始と創立記念日を飛ばして 1/7。
+	if got, want := cal.NextBusinessDay(date(2024, time.December, 27, 12)), date(2025, time.January, 7, 12); !got.Equal(want) {
+		t.Errorf("NextBusinessDay() = %v, want %v", got, want)
+	}
+	if cal.IsBusinessDay(date(2025, time.January, 3, 0)) {
+		t.Error("1/3 が営業日と判定された")
+	}
+
+	// 土日や祝日も営業する暦。
+	always := &jst.Calendar{Weekends: []time.Weekday{}, OpenOnHolidays: true}
+	if !always.IsBusinessDay(date(2025, time.January, 1, 0)) {
+		t.Error("祝日を営業日とする暦で元日が休業日と判定された")
+	}
+	if got := always.BusinessDaysBetween(date(2025, time.March, 1, 0), date(2025, time.March, 31, 0)); got != 30 {
+		t.Errorf("BusinessDaysBetween() = %d, want 30", got)
+	}
+}
+
+func TestAnnualClosure(t *testing.T) {
+	golden := jst.AnnualClosure(time.May, 1, time.May, 6)
+	if !golden(date(2025, time.May, 6, 0)) || golden(date(2025, time.May, 7, 0)) {
+		t.Error("年をまたがない期間の判定が誤っている")
+	}
+	if !jst.YearEndClosure(date(2025, time.December, 31, 0)) || jst.YearEndClosure(date(2025, time.January, 4, 0)) {
+		t.Error("年をまたぐ期間の判定が誤っている")
+	}
+}
+
+func TestCalendarWithoutBusinessDays(t *testing.T) {
+	calendars := map[string]*jst.Calendar{
+		"すべての曜日が休業日": {Weekends: []time.Weekday{
+			time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday,
+		}},
+		"毎日が休業日": {Closures: []jst.Closure{func(time.Time) bool { return true }}},
+	}
+	for name, c := range calendars {
+		t.Run(name, func(t *testing.T) {
+			start := date(2025, time.March, 3, 9)
+			if got := c.AddBusinessDays(start, 1); !got.IsZero() {
+				t.Errorf("AddBusinessDays() = %v, want zero", got)
+			}
+			if got := c.AddBusinessDays(start, -1); !got.IsZero() {
+				t.Errorf("AddBusinessDays(-1) = %v, want zero", got)
+			}
+			if got := c.NextBusinessDay(start); !got.IsZero() {
+				t.Errorf("NextBusinessDay() = %v, want zero", got)
+			}
+			if got := c.AddBusinessDays(start, 0); !got.Equal(start) {
+				t.Errorf("AddBusinessDays(0) = %v, want %v", got, start)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package jst
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Clock は現在時刻とタイマーの供給元です。
+//
+// 「今日」や「今週」に依存する処理で time.Now を直接呼ぶ代わりに Clock を受け取るようにすると、
+// テストで FakeClock を渡して日付の変わり目や祝日を決定的に再現できます。
+// 本番では SystemClock を渡してください。
+type Clock interface {
+	// Now は現在時刻を返します。
+	Now() time.Time
+	// NewTimer は d の経過後に 1 度だけ発火する Timer を作ります。
+	NewTimer(d time.Duration) Timer
+	// NewTicker は d ごとに発火する Ticker を作ります。d が 0 以下なら panic します。
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer は Clock が作るタイマーです。time.Timer と同じく、Stop や Reset の後に
+// 古い時刻がチャネルに残ることはありません。
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// Ticker は Clock が作るティッカーです。
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+	Reset(d time.Duration)
+}
+
+// SystemClock は time パッケージの実際の時刻を使う Clock です。
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) NewTimer(d time.Duration) Timer { return systemTimer{time.NewTimer(d)} }
+
+func (systemClock) NewTicker(d time.Duration) Ticker { return systemTicker{time.NewTicker(d)} }
+
+type systemTimer struct{ t *time.Timer }
+
+func (t systemTimer) C() <-chan time.Time        { return t.t.C }
+func (t systemTimer) Stop() bool                 { return t.t.Stop() }
+func (t systemTimer) Reset(d time.Duration) bool { return t.t.Reset(d) }
+
+type systemTicker struct{ t *time.Ticker }
+
+func (t systemTicker) C() <-chan time.Time   { return t.t.C }
+func (t systemTicker) Stop()                 { t.t.Stop() }
+func (t systemTicker) Reset(d time.Duration) { t.t.Reset(d) }
+
+// NowFrom は、clock の現在時刻を JST で返します。Now の Clock を受け取る版です。
+func NowFrom(clock Clock) time.Time {
+	return From(clock.Now())
+}
+
+// Today は、clock の現在時刻の JST の日付の 0 時を返します。
+func Today(clock Clock) time.Time {
+	return StartOfDay(clock.Now())
+}
+
+// TodayRange は、clock の現在時刻の JST の日付 1 日分の区間を返します。
+func TodayRange(clock Clock) Range {
+	return DayRange(clock.Now())
+}
+
+// FakeClock はテスト用の Clock です。時刻は Set か Advance を呼んだときだけ進みます。
+//
+// 時刻を進めると、その間に期限を迎えるタイマーとティッカーを期限の順に発火させます。
+// チャネルの容量は time.Timer と同じく 1 で、受け取られていない値があるときの発火は捨てます。
+// 並行に使えます。
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+// NewFakeClock は now を現在時刻とする FakeClock を作ります。
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now は現在の時刻を返します。
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set は現在時刻を t にします。t が現在より後なら、その間に期限を迎えるタイマーを発火させます。
+// 現在より前に戻した場合は何も発火させません。
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !t.After(c.now) {
+		c.now = t
+		return
+	}
+	c.advanceTo(t)
+}
+
+// Advance は現在時刻を d だけ進め、その間に期限を迎えるタイマーを発火させます。
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.advanceTo(c.now.Add(d))
+}
+
+// advanceTo は、期限の早い順にタイマーを発火させながら target まで時刻を進めます。
+// ティッカーは次の期限を設定し直すため、1 回の Advance で複数回発火することがあります。
+func (c *FakeClock) advanceTo(target time.Time) {
+	for {
+		i := -1
+		for j, w := range c.waiters {
+			if i < 0 || w.deadline.Before(c.waiters[i].deadline) {
+				i = j
+			}
+		}
+		if i < 0 || c.waiters[i].deadline.After(target) {
+			break
+		}
+		w := c.waiters[i]
+		c.now = w.deadline
+		w.fire(c.now)
+		if w.period > 0 {
+			w.deadline = w.deadline.Add(w.period)
+		} else {
+			c.waiters = slices.Delete(c.waiters, i, i+1)
+		}
+	}
+	c.now = target
+}
+
+// Waiters は、まだ発火していないタイマーと動作中のティッカーの数を返します。
+// 別の goroutine がタイマーを作るのを待ってから Advance するときに使います。
+func (c *FakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+// NewTimer は、現在時刻から d の経過後に発火する Timer を作ります。
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	w := &fakeWaiter{clock: c, ch: make(chan time.Time, 1)}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.schedule(w, d)
+	return w
+}
+
+// NewTicker は、現在時刻から d ごとに発火する Ticker を作ります。
+func (c *FakeClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("jst: non-positive interval for NewTicker")
+	}
+	w := &fakeWaiter{clock: c, ch: make(chan time.Time, 1), period: d}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.schedule(w, d)
+	return fakeTicker{w}
+}
+
+// schedule は w の期限を現在時刻から d 後にします。d が 0 以下なら直ちに発火させます。
+func (c *FakeClock) schedule(w *fakeWaiter, d time.Duration) {
+	if d <= 0 && w.period == 0 {
+		w.fire(c.now)
+		return
+	}
+	w.deadline = c.now.Add(d)
+	c.waiters = append(c.waiters, w)
+}
+
+// unschedule は w を待ち行列から外し、外したかどうかを返します。
+func (c *FakeClock) unschedule(w *fakeWaiter) bool {
+	i := slices.Index(c.waiters, w)
+	if i < 0 {
+		return false
+	}
+	c.waiters = slices.Delete(c.waiters, i, i+1)
+	return true
+}
+
+// fakeWaiter は FakeClock のタイマーとティッカーです。period が 0 ならタイマーです。
+type fakeWaiter struct {
+	clock    *FakeClock
+	ch       chan time.Time
+	deadline time.Time
+	period   time.Duration
+}
+
+func (w *fakeWaiter) fire(now time.Time) {
+	select {
+	case w.ch <- now:
+	default:
+	}
+}
+
+// drain は、Stop や Reset の前に発火した値を捨てます。
+func (w *fakeWaiter) drain() {
+	select {
+	case <-w.ch:
+	default:
+	}
+}
+
+func (w *fakeWaiter) C() <-chan time.Time { return w.ch }
+
+// Stop はタイマーを止め、発火前に止めたなら true を返します。
+func (w *fakeWaiter) Stop() bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+	w.drain()
+	return w.clock.unschedule(w)
+}
+
+// Reset は期限を現在時刻から d 後に設定し直し、発火前だったなら true を返します。
+func (w *fakeWaiter) Reset(d time.Duration) bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+	w.drain()
+	active := w.clock.unschedule(w)
+	w.clock.schedule(w, d)
+	return active
+}
+
+// fakeTicker は fakeWaiter を Ticker として使うための型です。
+type fakeTicker struct{ w *fakeWaiter }
+
+func (t fakeTicker) C() <-chan time.Time { return t.w.C() }
+
+func (t fakeTicker) Stop() { t.w.Stop() }
+
+func (t fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("jst: non-positive interval for Ticker.Reset")
+	}
+	t.w.clock.mu.Lock()
+	defer t.w.clock.mu.Unlock()
+	t.w.drain()
+	t.w.clock.unschedule(t.w)
+	t.w.period = d
+	t.w.clock.schedule(t.w, d)
+}
@@ -0,0 +1,165 @@
+package jst_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shouni/go-utils/jst"
+)
+
+// received は ch に届いている値を待たずに返します。
+func received(ch <-chan time.Time) (time.Time, bool) {
+	select {
+	case t := <-ch:
+		return t, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// TestFakeClockDayBoundary は、FakeClock で日付の変わり目を再現できることを確認します。
+func TestFakeClockDayBoundary(t *testing.T) {
+	// JST の 2025-12-31 23:59:59。
+	clock := jst.NewFakeClock(time.Date(2025, time.December, 31, 14, 59, 59, 0, time.UTC))
+
+	if got := jst.Today(clock); !got.Equal(date(2025, time.December, 31, 0)) {
+		t.Errorf("Today() = %v, want 2025-12-31", got)
+	}
+	if jst.IsHoliday(jst.NowFrom(clock)) {
+		t.Error("大晦日が祝日と判定された")
+	}
+
+	clock.Advance(time.Second)
+	if got := jst.Today(clock); !got.Equal(date(2026, time.January, 1, 0)) {
+		t.Errorf("Advance 後の Today() = %v, want 2026-01-01", got)
+	}
+	if !jst.IsHoliday(jst.NowFrom(clock)) {
+		t.Error("元日が祝日と判定されない")
+	}
+	if r := jst.TodayRange(clock); !r.Start.Equal(jst.Today(clock)) || r.Duration() != 24*time.Hour {
+		t.Errorf("TodayRange() = %v", r)
+	}
+	if got := jst.NowFrom(clock).Location(); got != jst.Location() {
+		t.Errorf("NowFrom().Location() = %v, want JST", got)
+	}
+
+	// 過去へ戻すこともできる。
+	clock.Set(date(2025, time.May, 5, 12))
+	if name, _ := jst.HolidayName(jst.NowFrom(clock)); name != "こどもの日" {
+		t.Errorf("HolidayName() = %q, want こどもの日", name)
+	}
+}
+
+func TestFakeClockTimer(t *testing.T) {
+	start := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clock := jst.NewFakeClock(start)
+	timer := clock.NewTimer(time.Minute)
+
+	clock.Advance(59 * time.Second)
+	if _, ok := received(timer.C()); ok {
+		t.Fatal("期限前に発火した")
+	}
+	clock.Advance(2 * time.Second)
+	if got, ok := received(timer.C()); !ok || !got.Equal(start.Add(time.Minute)) {
+		t.Fatalf("発火 = %v, %v, want 期限の時刻", got, ok)
+	}
+	if clock.Waiters() != 0 {
+		t.Errorf("Waiters() = %d, want 0", clock.Waiters())
+	}
+
+	// 発火後の Reset は false を返し、新しい期限で発火する。
+	if timer.Reset(time.Second) {
+		t.Error("発火済みのタイマーの Reset が true を返した")
+	}
+	if !timer.Stop() {
+		t.Error("発火前のタイマーの Stop が false を返した")
+	}
+	clock.Advance(time.Hour)
+	if _, ok := received(timer.C()); ok {
+		t.Error("止めたタイマーが発火した")
+	}
+
+	// Reset は、受け取られずに残っていた値を捨てる。
+	timer.Reset(time.Second)
+	clock.Advance(time.Second)
+	timer.Reset(time.Second)
+	if _, ok := received(timer.C()); ok {
+		t.Error("Reset 前の値がチャネルに残っている")
+	}
+
+	// 期限が 0 以下のタイマーは直ちに発火する。
+	if _, ok := received(clock.NewTimer(0).C()); !ok {
+		t.Error("期限 0 のタイマーが発火しない")
+	}
+}
+
+func TestFakeClockTicker(t *testing.T) {
+	start := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clock := jst.NewFakeClock(start)
+	ticker := clock.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	for i := 1; i <= 3; i++ {
+		clock.Advance(10 * time.Second)
+		if got, ok := received(ticker.C()); !ok || !got.Equal(start.Add(time.Duration(i)*10*time.Second)) {
+			t.Fatalf("%d 回目の発火 = %v, %v", i, got, ok)
+		}
+	}
+
+	// 受け取らないうちに何回も期限を迎えた場合、値は 1 つだけ残る。
+	clock.Advance(time.Minute)
+	if _, ok := received(ticker.C()); !ok {
+		t.Fatal("発火しない")
+	}
+	if _, ok := received(ticker.C()); ok {
+		t.Error("容量 1 を超えて値が残っている")
+	}
+	if !clock.Now().Equal(start.Add(90 * time.Second)) {
+		t.Errorf("Now() = %v, want Advance した合計", clock.Now())
+	}
+
+	ticker.Reset(time.Hour)
+	clock.Advance(time.Minute)
+	if _, ok := received(ticker.C()); ok {
+		t.Error("Reset 後の周期より前に発火した")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("周期 0 の NewTicker が panic しない")
+		}
+	}()
+	clock.NewTicker(0)
+}
+
+// TestFakeClockWithGoroutine は、別の goroutine で待つ処理を Waiters と Advance で進められることを確認します。
+func TestFakeClockWithGoroutine(t *testing.T) {
+	clock := jst.NewFakeClock(time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC))
+	done := make(chan time.Time)
+	go func() {
+		timer := clock.NewTimer(time.Hour)
+		done <- <-timer.C()
+	}()
+
+	for clock.Waiters() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	clock.Advance(time.Hour)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Advance しても待っている goroutine が再開しない")
+	}
+}
+
+func TestSystemClock(t *testing.T) {
+	timer := jst.SystemClock.NewTimer(time.Millisecond)
+	select {
+	case <-timer.C():
+	case <-time.After(time.Second):
+		t.Fatal("SystemClock のタイマーが発火しない")
+	}
+	if d := time.Since(jst.SystemClock.Now()); d < 0 || d > time.Minute {
+		t.Errorf("SystemClock.Now() が現在時刻と離れている: %v", d)
+	}
+}
@@ -0,0 +1,172 @@
+package jst
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+)
+
+// Date は時刻を持たない暦日です。誕生日、配送日、集計日のように「JST の何日か」を表します。
+//
+// time.Time の 0 時で日付を表すと、UTC で直列化したときに前日になる取り違えが起きます。
+// Date はロケーションを持たないため、そのような変換が起こりません。
+// 比較演算子 == で比べられ、マップのキーにも使えます。ゼロ値は日付が無いことを表します。
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// DateOf は t を JST に変換した日付を返します。
+func DateOf(t time.Time) Date {
+	t = From(t)
+	return Date{Year: t.Year(), Month: t.Month(), Day: t.Day()}
+}
+
+// ParseDate は "2006-01-02" 形式の文字列を Date に変換します。
+func ParseDate(value string) (Date, error) {
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return Date{}, fmt.Errorf("parse date %q: %w", value, err)
+	}
+	return Date{Year: t.Year(), Month: t.Month(), Day: t.Day()}, nil
+}
+
+// Time は、その日の JST の 0 時を返します。
+func (d Date) Time() time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, Location())
+}
+
+// utc は日数の計算に使う、その日の UTC の 0 時です。夏時間の影響を受けないよう UTC で計算します。
+func (d Date) utc() time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, time.UTC)
+}
+
+// String は "2006-01-02" 形式の文字列を返します。
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, int(d.Month), d.Day)
+}
+
+// IsZero はゼロ値かを返します。
+func (d Date) IsZero() bool {
+	return d == Date{}
+}
+
+// IsValid は、実在する日付かを返します。2 月 30 日のような値や、ゼロ値は false です。
+func (d Date) IsValid() bool {
+	return d.Month >= time.January && d.Month <= time.December && DateOf(d.Time()) == d
+}
+
+// Weekday は曜日を返します。
+func (d Date) Weekday() time.Weekday {
+	return d.utc().Weekday()
+}
+
+// AddDays は n 日後の日付を返します。n が負なら n 日前です。
+func (d Date) AddDays(n int) Date {
+	t := d.utc().AddDate(0, 0, n)
+	return Date{Year: t.Year(), Month: t.Month(), Day: t.Day()}
+}
+
+// Before は d が u より前の日付かを返します。
+func (d Date) Before(u Date) bool {
+	return d.Compare(u) < 0
+}
+
+// After は d が u より後の日付かを返します。
+func (d Date) After(u Date) bool {
+	return d.Compare(u) > 0
+}
+
+// Compare は、d が u より前なら -1、後なら +1、同じ日なら 0 を返します。
+func (d Date) Compare(u Date) int {
+	return d.utc().Compare(u.utc())
+}
+
+// Sub は d から u までの日数 (d - u) を返します。
+func (d Date) Sub(u Date) int {
+	return int(d.utc().Sub(u.utc()) / (24 * time.Hour))
+}
+
+// MarshalText は "2006-01-02" 形式で書き出します。ゼロ値は空文字です。
+func (d Date) MarshalText() ([]byte, error) {
+	if d.IsZero() {
+		return []byte{}, nil
+	}
+	if !d.IsValid() {
+		return nil, fmt.Errorf("marshal date: invalid date %s", d)
+	}
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText は "2006-01-02" 形式を読み込みます。空文字はゼロ値です。
+func (d *Date) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*d = Date{}
+		return nil
+	}
+	parsed, err := ParseDate(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalJSON は "2006-01-02" 形式の文字列で書き出します。ゼロ値は null です。
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	text, err := d.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(`"` + string(text) + `"`), nil
+}
+
+// UnmarshalJSON は "2006-01-02" 形式の文字列を読み込みます。null と空文字はゼロ値です。
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*d = Date{}
+		return nil
+	}
+	if len(data) < 2 || data[0] != '"' || data[len(data)-1] != '"' {
+		return fmt.Errorf("unmarshal date: not a JSON string: %s", data)
+	}
+	return d.UnmarshalText(data[1 : len(data)-1])
+}
+
+// Scan は sql.Scanner を実装します。DATE 型の列を読み込めます。
+//
+// ドライバーが time.Time を返す場合は、JST へ変換せずにその値のロケーションでの日付を使います。
+// DATE 型の値は多くのドライバーで UTC の 0 時として返るためです。NULL はゼロ値です。
+func (d *Date) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*d = Date{}
+		return nil
+	case time.Time:
+		*d = Date{Year: v.Year(), Month: v.Month(), Day: v.Day()}
+		return nil
+	case string:
+		return d.UnmarshalText([]byte(v))
+	case []byte:
+		return d.UnmarshalText(v)
+	default:
+		return fmt.Errorf("scan date: unsupported type %T", src)
+	}
+}
+
+// Value は driver.Valuer を実装します。"2006-01-02" 形式の文字列を渡し、ゼロ値は NULL です。
+//
+// time.Time で渡すと、ドライバーが UTC へ変換して前日になることがあるため文字列にしています。
+func (d Date) Value() (driver.Value, error) {
+	if d.IsZero() {
+		return nil, nil
+	}
+	if !d.IsValid() {
+		return nil, fmt.Errorf("date value: invalid date %s", d)
+	}
+	return d.String(), nil
+}
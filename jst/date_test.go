@@ -0,0 +1,144 @@
+package jst_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/shouni/go-utils/jst"
+)
+
+var (
+	_ sql.Scanner   = (*jst.Date)(nil)
+	_ driver.Valuer = jst.Date{}
+)
+
+func TestDateOf(t *testing.T) {
+	// UTC の 2026-07-24 15:00 は JST の 2026-07-25 00:00。
+	if got, want := jst.DateOf(time.Date(2026, time.July, 24, 15, 0, 0, 0, time.UTC)), (jst.Date{Year: 2026, Month: time.July, Day: 25}); got != want {
+		t.Errorf("DateOf() = %v, want %v", got, want)
+	}
+
+	d := jst.Date{Year: 2026, Month: time.July, Day: 25}
+	if got := d.Time(); !got.Equal(date(2026, time.July, 25, 0)) || got.Location() != jst.Location() {
+		t.Errorf("Time() = %v, want JST の 0 時", got)
+	}
+	if d.Weekday() != time.Saturday {
+		t.Errorf("Weekday() = %v, want Saturday", d.Weekday())
+	}
+}
+
+func TestDateArithmetic(t *testing.T) {
+	d := jst.Date{Year: 2028, Month: time.February, Day: 28}
+	tests := []struct {
+		n    int
+		want jst.Date
+	}{
+		{1, jst.Date{Year: 2028, Month: time.February, Day: 29}},
+		{2, jst.Date{Year: 2028, Month: time.March, Day: 1}},
+		{-59, jst.Date{Year: 2027, Month: time.December, Day: 31}},
+		{366, jst.Date{Year: 2029, Month: time.February, Day: 28}},
+	}
+	for _, tt := range tests {
+		got := d.AddDays(tt.n)
+		if got != tt.want {
+			t.Errorf("AddDays(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+		if diff := got.Sub(d); diff != tt.n {
+			t.Errorf("%v.Sub(%v) = %d, want %d", got, d, diff, tt.n)
+		}
+		if (tt.n > 0) != d.Before(got) || (tt.n > 0) != got.After(d) {
+			t.Errorf("Before/After の結果が %v と %v で一致しない", d, got)
+		}
+	}
+	if d.Compare(d) != 0 || d.Before(d) || d.After(d) {
+		t.Error("同じ日付の比較が誤っている")
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	got, err := jst.ParseDate("2026-07-25")
+	if err != nil || got != (jst.Date{Year: 2026, Month: time.July, Day: 25}) {
+		t.Errorf("ParseDate() = %v, %v", got, err)
+	}
+	for _, value := range []string{"2026-02-30", "2026/07/25", "2026-7-25", ""} {
+		if _, err := jst.ParseDate(value); err == nil {
+			t.Errorf("ParseDate(%q) error = nil, want error", value)
+		}
+	}
+	if (jst.Date{Year: 2026, Month: time.February, Day: 30}).IsValid() || (jst.Date{}).IsValid() {
+		t.Error("実在しない日付が IsValid と判定された")
+	}
+}
+
+func TestDateJSON(t *testing.T) {
+	type delivery struct {
+		On   jst.Date         `json:"on"`
+		Prev jst.Date         `json:"prev"`
+		Map  map[jst.Date]int `json:"map"`
+	}
+	in := delivery{
+		On:  jst.Date{Year: 2026, Month: time.July, Day: 5},
+		Map: map[jst.Date]int{{Year: 2026, Month: time.July, Day: 6}: 3},
+	}
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"on":"2026-07-05","prev":null,"map":{"2026-07-06":3}}`; string(b) != want {
+		t.Errorf("Marshal() = %s, want %s", b, want)
+	}
+
+	var out delivery
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.On != in.On || !out.Prev.IsZero() || out.Map[jst.Date{Year: 2026, Month: time.July, Day: 6}] != 3 {
+		t.Errorf("Unmarshal() = %+v, want %+v", out, in)
+	}
+
+	for _, data := range []string{`"2026-13-01"`, `20260705`, `{"on":1}`} {
+		var d jst.Date
+		if err := json.Unmarshal([]byte(data), &d); err == nil {
+			t.Errorf("Unmarshal(%s) error = nil, want error", data)
+		}
+	}
+	if _, err := json.Marshal(jst.Date{Year: 2026, Month: time.February, Day: 30}); err == nil {
+		t.Error("実在しない日付の Marshal がエラーにならない")
+	}
+}
+
+func TestDateSQL(t *testing.T) {
+	want := jst.Date{Year: 2026, Month: time.July, Day: 25}
+	tests := []struct {
+		name string
+		src  any
+		want jst.Date
+	}{
+		{"UTC の 0 時", time.Date(2026, time.July, 25, 0, 0, 0, 0, time.UTC), want},
+		{"文字列", "2026-07-25", want},
+		{"バイト列", []byte("2026-07-25"), want},
+		{"NULL", nil, jst.Date{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := jst.Date{Year: 1999, Month: time.January, Day: 1}
+			if err := d.Scan(tt.src); err != nil || d != tt.want {
+				t.Errorf("Scan(%v) = %v, %v, want %v", tt.src, d, err, tt.want)
+			}
+		})
+	}
+	var d jst.Date
+	if err := d.Scan(42); err == nil {
+		t.Error("Scan(int) error = nil, want error")
+	}
+
+	if v, err := want.Value(); err != nil || v != "2026-07-25" {
+		t.Errorf("Value() = %v, %v, want 2026-07-25", v, err)
+	}
+	if v, err := (jst.Date{}).Value(); err != nil || v != nil {
+		t.Errorf("ゼロ値の Value() = %v, %v, want nil", v, err)
+	}
+}
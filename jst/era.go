@@ -0,0 +1,232 @@
+package jst
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 和暦の変換で返すエラーです。errors.Is で判定できます。
+var (
+	// ErrOutOfEra は、日付が対応する元号の範囲外であることを表します。
+	ErrOutOfEra = errors.New("date is outside the era")
+
+	// ErrInvalidEraDate は、和暦の文字列として解釈できないことを表します。
+	ErrInvalidEraDate = errors.New("invalid era date")
+)
+
+// Era は元号です。
+type Era struct {
+	// Name は漢字の元号名です。例: "令和"
+	Name string
+	// Abbr はアルファベット 1 文字の略記です。例: "R"
+	Abbr string
+	// Start は改元日の JST の 0 時です。
+	Start time.Time
+}
+
+// eraDef は元号の定義です。
+type eraDef struct {
+	name, abbr string
+	start      int // 改元日を yyyymmdd で表したもの
+}
+
+// eras は対応する元号を新しい順に並べたものです。
+//
+// 明治の開始日は改元の詔が出された 1868 年 10 月 23 日（グレゴリオ暦）とします。
+// 明治 5 年までは太陰太陽暦が使われていたため、それ以前の和暦の日付とは一致しません。
+//
+// Location を初期化時に読み込まないよう、開始日は time.Time ではなく数値で持ちます。
+var eras = []eraDef{
+	{"令和", "R", 20190501},
+	{"平成", "H", 19890108},
+	{"昭和", "S", 19261225},
+	{"大正", "T", 19120730},
+	{"明治", "M", 18681023},
+}
+
+func (d eraDef) era() Era {
+	start := time.Date(d.start/10000, time.Month(d.start/100%100), d.start%100, 0, 0, 0, 0, Location())
+	return Era{Name: d.name, Abbr: d.abbr, Start: start}
+}
+
+// civilNumber は t の日付を yyyymmdd の数値で返します。
+func civilNumber(t time.Time) int {
+	return t.Year()*10000 + int(t.Month())*100 + t.Day()
+}
+
+// EraStyle は和暦の表記です。
+type EraStyle int
+
+const (
+	// EraLong は "令和8年7月25日" の形式です。
+	EraLong EraStyle = iota
+	// EraShort は "R8.07.25" の形式です。月と日は 2 桁にそろえます。
+	EraShort
+)
+
+// EraOptions は FormatEra の設定です。ゼロ値は EraLong で、1 年を "1年" と書きます。
+type EraOptions struct {
+	Style EraStyle
+
+	// Gannen が true なら、EraLong で 1 年を "元年" と書きます。
+	Gannen bool
+
+	// FullWidth が true なら、数字・記号・略記の英字を全角で書きます。
+	FullWidth bool
+}
+
+// EraOf は、t を JST に変換した日付の元号と和暦の年を返します。
+// 明治より前の日付は ErrOutOfEra を返します。
+func EraOf(t time.Time) (Era, int, error) {
+	t = From(t)
+	for _, d := range eras {
+		if civilNumber(t) >= d.start {
+			return d.era(), t.Year() - d.start/10000 + 1, nil
+		}
+	}
+	return Era{}, 0, fmt.Errorf("%w: %s", ErrOutOfEra, t.Format(time.DateOnly))
+}
+
+// FormatEra は、t を JST に変換した日付を和暦で表します。
+// 明治より前の日付は ErrOutOfEra を返します。
+func FormatEra(t time.Time, opts EraOptions) (string, error) {
+	era, year, err := EraOf(t)
+	if err != nil {
+		return "", err
+	}
+	t = From(t)
+
+	var s string
+	switch opts.Style {
+	case EraShort:
+		s = fmt.Sprintf("%s%d.%02d.%02d", era.Abbr, year, int(t.Month()), t.Day())
+	default:
+		y := strconv.Itoa(year)
+		if year == 1 && opts.Gannen {
+			y = "元"
+		}
+		s = fmt.Sprintf("%s%s年%d月%d日", era.Name, y, int(t.Month()), t.Day())
+	}
+	if opts.FullWidth {
+		s = toFullWidth(s)
+	}
+	return s, nil
+}
+
+// ParseEra は和暦の文字列を解釈し、その日の JST の 0 時を返します。
+//
+// "令和8年7月25日"、"令和元年5月1日"、"R8.07.25"、"R8/7/25" のような形式を受け付けます。
+// 数字と英字は全角でもかまわず、英字の大文字小文字は区別しません。年月日の区切りには空白を
+// 挟めます。元号の範囲外の日付（"令和元年4月30日" や "平成32年1月1日"）は ErrOutOfEra を、
+// 解釈できない文字列は ErrInvalidEraDate をラップしたエラーを返します。
+func ParseEra(value string) (time.Time, error) {
+	s := strings.Join(strings.Fields(toHalfWidth(value)), "")
+
+	era, rest, ok := cutEra(s)
+	if !ok {
+		return time.Time{}, fmt.Errorf("parse era date %q: %w", value, ErrInvalidEraDate)
+	}
+
+	var year int
+	if rest, ok = strings.CutPrefix(rest, "元"); ok {
+		year = 1
+	} else if year, rest, ok = cutNumber(rest); !ok {
+		return time.Time{}, fmt.Errorf("parse era date %q: %w", value, ErrInvalidEraDate)
+	}
+	month, day, ok := cutMonthDay(rest)
+	if !ok {
+		return time.Time{}, fmt.Errorf("parse era date %q: %w", value, ErrInvalidEraDate)
+	}
+
+	t := time.Date(era.Start.Year()+year-1, time.Month(month), day, 0, 0, 0, 0, Location())
+	if year < 1 || t.Month() != time.Month(month) || t.Day() != day {
+		return time.Time{}, fmt.Errorf("parse era date %q: %w", value, ErrInvalidEraDate)
+	}
+	if got, _, err := EraOf(t); err != nil || got.Name != era.Name {
+		return time.Time{}, fmt.Errorf("parse era date %q: %w: %s is not in %s", value, ErrOutOfEra, t.Format(time.DateOnly), era.Name)
+	}
+	return t, nil
+}
+
+// cutEra は s の先頭の元号名または略記を取り除きます。
+func cutEra(s string) (Era, string, bool) {
+	for _, d := range eras {
+		if rest, ok := strings.CutPrefix(s, d.name); ok {
+			return d.era(), rest, true
+		}
+		if len(s) > 0 && strings.EqualFold(s[:1], d.abbr) {
+			return d.era(), s[1:], true
+		}
+	}
+	return Era{}, "", false
+}
+
+// cutMonthDay は、年の後に続く "7月25日" または ".07.25" のような月日を解釈します。
+// 略記の区切りは "."、"/"、"-" のいずれかで、2 か所とも同じ文字でなければなりません。
+func cutMonthDay(s string) (month, day int, ok bool) {
+	if rest, found := strings.CutPrefix(s, "年"); found {
+		if month, rest, ok = cutNumber(rest); !ok {
+			return 0, 0, false
+		}
+		if rest, ok = strings.CutPrefix(rest, "月"); !ok {
+			return 0, 0, false
+		}
+		day, rest, ok = cutNumber(rest)
+		return month, day, ok && rest == "日"
+	}
+
+	if s == "" || !strings.ContainsRune("./-", rune(s[0])) {
+		return 0, 0, false
+	}
+	sep := s[:1]
+	if month, s, ok = cutNumber(s[1:]); !ok {
+		return 0, 0, false
+	}
+	if s, ok = strings.CutPrefix(s, sep); !ok {
+		return 0, 0, false
+	}
+	day, s, ok = cutNumber(s)
+	return month, day, ok && s == ""
+}
+
+// cutNumber は s の先頭の 10 進数を取り除きます。
+func cutNumber(s string) (int, string, bool) {
+	i := 0
+	for i < len(s) && '0' <= s[i] && s[i] <= '9' {
+		i++
+	}
+	if i == 0 || i > 4 {
+		return 0, s, false
+	}
+	n, _ := strconv.Atoi(s[:i])
+	return n, s[i:], true
+}
+
+// 全角の英数字・記号 (U+FF01〜U+FF5E) と ASCII の差です。
+const fullWidthOffset = 0xFEE0
+
+// toFullWidth は ASCII の英数字と記号を全角にします。
+func toFullWidth(s string) string {
+	return strings.Map(func(r rune) rune {
+		if '!' <= r && r <= '~' {
+			return r + fullWidthOffset
+		}
+		return r
+	}, s)
+}
+
+// toHalfWidth は全角の英数字と記号、全角の空白を ASCII にします。
+func toHalfWidth(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case '！' <= r && r <= '～':
+			return r - fullWidthOffset
+		case r == '　':
+			return ' '
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,121 @@
+package jst_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shouni/go-utils/jst"
+)
+
+func TestFormatEra(t *testing.T) {
+	tests := []struct {
+		date time.Time
+		opts jst.EraOptions
+		want string
+	}{
+		{date(2026, time.July, 25, 0), jst.EraOptions{}, "令和8年7月25日"},
+		{date(2026, time.July, 25, 0), jst.EraOptions{Style: jst.EraShort}, "R8.07.25"},
+		{date(2026, time.July, 25, 0), jst.EraOptions{FullWidth: true}, "令和８年７月２５日"},
+		{date(2026, time.July, 25, 0), jst.EraOptions{Style: jst.EraShort, FullWidth: true}, "Ｒ８．０７．２５"},
+		{date(2019, time.May, 1, 0), jst.EraOptions{Gannen: true}, "令和元年5月1日"},
+		{date(2019, time.May, 1, 0), jst.EraOptions{}, "令和1年5月1日"},
+		{date(2019, time.April, 30, 23), jst.EraOptions{Gannen: true}, "平成31年4月30日"},
+		{date(1989, time.January, 7, 0), jst.EraOptions{}, "昭和64年1月7日"},
+		{date(1989, time.January, 8, 0), jst.EraOptions{Style: jst.EraShort}, "H1.01.08"},
+		{date(1926, time.December, 25, 0), jst.EraOptions{Gannen: true}, "昭和元年12月25日"},
+		{date(1912, time.July, 29, 0), jst.EraOptions{Style: jst.EraShort}, "M45.07.29"},
+		{date(1912, time.July, 30, 0), jst.EraOptions{Style: jst.EraShort}, "T1.07.30"},
+		// UTC の時刻は JST の日付で判定する。
+		{time.Date(2019, time.April, 30, 15, 0, 0, 0, time.UTC), jst.EraOptions{Gannen: true}, "令和元年5月1日"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			got, err := jst.FormatEra(tt.date, tt.opts)
+			if err != nil {
+				t.Fatalf("FormatEra() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FormatEra(%v) = %q, want %q", tt.date, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := jst.FormatEra(date(1868, time.October, 22, 0), jst.EraOptions{}); !errors.Is(err, jst.ErrOutOfEra) {
+		t.Errorf("明治より前の FormatEra() error = %v, want ErrOutOfEra", err)
+	}
+}
+
+func TestParseEra(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"令和8年7月25日", date(2026, time.July, 25, 0)},
+		{"令和元年5月1日", date(2019, time.May, 1, 0)},
+		{"令和 8年 7月 25日", date(2026, time.July, 25, 0)},
+		{"令和８年７月２５日", date(2026, time.July, 25, 0)},
+		{"R8.07.25", date(2026, time.July, 25, 0)},
+		{"r8/7/25", date(2026, time.July, 25, 0)},
+		{"Ｈ３１－０４－３０", date(2019, time.April, 30, 0)},
+		{"S64.1.7", date(1989, time.January, 7, 0)},
+		{"明治45年7月29日", date(1912, time.July, 29, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := jst.ParseEra(tt.value)
+			if err != nil {
+				t.Fatalf("ParseEra() error = %v", err)
+			}
+			if !got.Equal(tt.want) || got.Location() != jst.Location() {
+				t.Errorf("ParseEra(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEraErrors(t *testing.T) {
+	tests := []struct {
+		value string
+		want  error
+	}{
+		{"令和元年4月30日", jst.ErrOutOfEra},
+		{"平成32年1月1日", jst.ErrOutOfEra},
+		{"S64.01.08", jst.ErrOutOfEra},
+		{"令和8年2月30日", jst.ErrInvalidEraDate},
+		{"令和0年1月1日", jst.ErrInvalidEraDate},
+		{"令和8年7月", jst.ErrInvalidEraDate},
+		{"R8.07/25", jst.ErrInvalidEraDate},
+		{"X8.07.25", jst.ErrInvalidEraDate},
+		{"2026-07-25", jst.ErrInvalidEraDate},
+		{"", jst.ErrInvalidEraDate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if _, err := jst.ParseEra(tt.value); !errors.Is(err, tt.want) {
+				t.Errorf("ParseEra(%q) error = %v, want %v", tt.value, err, tt.want)
+			}
+		})
+	}
+}
+
+// TestEraRoundTrip は、各元号の境目で FormatEra と ParseEra が往復することを確認します。
+func TestEraRoundTrip(t *testing.T) {
+	for _, d := range []time.Time{
+		date(1868, time.October, 23, 0),
+		date(1912, time.July, 30, 0),
+		date(1926, time.December, 24, 0),
+		date(1989, time.January, 8, 0),
+		date(2019, time.May, 1, 0),
+	} {
+		for _, opts := range []jst.EraOptions{{Gannen: true}, {Style: jst.EraShort, FullWidth: true}} {
+			s, err := jst.FormatEra(d, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, err := jst.ParseEra(s); err != nil || !got.Equal(d) {
+				t.Errorf("ParseEra(%q) = %v, %v, want %v", s, got, err, d)
+			}
+		}
+	}
+}
@@ -35,3 +35,105 @@ func ExampleParse() {
 	fmt.Println(jst.Format(t, "15時04分"))
 	// Output: 18時30分
 }
+
+func ExampleHolidayName() {
+	t := time.Date(2025, time.February, 24, 12, 0, 0, 0, jst.Location())
+	name, ok := jst.HolidayName(t)
+	fmt.Println(name, ok)
+	// Output: 振替休日 true
+}
+
+func ExampleCalendar_AddBusinessDays() {
+	// 土日・祝日・年末年始を休業日とする暦で、仕事納めの日から 3 営業日後を求める。
+	cal := &jst.Calendar{Closures: []jst.Closure{jst.YearEndClosure}}
+	t := time.Date(2025, time.December, 26, 17, 0, 0, 0, jst.Location())
+	fmt.Println(cal.AddBusinessDays(t, 3).Format("2006-01-02 15:04"))
+	// Output: 2026-01-07 17:00
+}
+
+func ExampleFormatEra() {
+	t := time.Date(2019, time.May, 1, 9, 0, 0, 0, jst.Location())
+	long, _ := jst.FormatEra(t, jst.EraOptions{Gannen: true})
+	short, _ := jst.FormatEra(t, jst.EraOptions{Style: jst.EraShort})
+	fmt.Println(long, short)
+	// Output: 令和元年5月1日 R1.05.01
+}
+
+func ExampleParseEra() {
+	t, err := jst.ParseEra("Ｒ８．０７．２５")
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	fmt.Println(t.Format(time.DateOnly))
+	// Output: 2026-07-25
+}
+
+func ExampleFormatJapanese() {
+	t := time.Date(2026, time.July, 25, 15, 4, 0, 0, jst.Location())
+	fmt.Println(jst.FormatJapanese(t, jst.LayoutJapaneseDateTime))
+	fmt.Println(jst.FormatJapanese(t, jst.LayoutJapaneseTime))
+	// Output:
+	// 2026年7月25日(土) 15:04
+	// 午後3時4分
+}
+
+func ExampleDayRange() {
+	// 「今日の分」を UTC で保存した行から検索する条件を作る。
+	now := time.Date(2026, time.February, 28, 23, 59, 0, 0, time.UTC)
+	r := jst.DayRange(now).UTC()
+	fmt.Println(r.Start.Format(time.RFC3339), r.End.Format(time.RFC3339))
+	// Output: 2026-02-28T15:00:00Z 2026-03-01T15:00:00Z
+}
+
+func ExampleDateOf() {
+	// UTC で保存された時刻から、JST の配送日を取り出す。
+	shippedAt := time.Date(2026, time.July, 24, 16, 30, 0, 0, time.UTC)
+	d := jst.DateOf(shippedAt)
+	fmt.Println(d, d.AddDays(2), d.AddDays(2).Sub(d))
+	// Output: 2026-07-25 2026-07-27 2
+}
+
+func ExampleFormatRelative() {
+	now := time.Date(2026, time.July, 25, 0, 30, 0, 0, jst.Location())
+	fmt.Println(jst.FormatRelative(now.Add(-3*time.Minute), now, jst.RelativeOptions{}))
+	fmt.Println(jst.FormatRelative(now.Add(-90*time.Minute), now, jst.RelativeOptions{}))
+	fmt.Println(jst.FormatRelative(now.AddDate(0, 0, 2), now, jst.RelativeOptions{}))
+	// Output:
+	// 3分前
+	// 昨日 23:00
+	// 2日後
+}
+
+func ExampleFormatDuration() {
+	fmt.Println(jst.FormatDuration(83*time.Minute+45*time.Second, jst.DurationOptions{}))
+	// Output: 1時間23分
+}
+
+func ExampleParseAny() {
+	// 入力欄やレガシーデータのように、書式がそろっていない値を解釈する。
+	for _, value := range []string{"2026/7/25", "２０２６年７月２５日", "2026-07-25T06:04:00Z"} {
+		t, layout, err := jst.ParseAny(value)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		fmt.Println(jst.Format(t, jst.LayoutDisplay), layout)
+	}
+	// Output:
+	// 2026-07-25 00:00 JST 2006/1/2
+	// 2026-07-25 00:00 JST 2006年1月2日
+	// 2026-07-25 15:04 JST 2006-01-02T15:04:05Z07:00
+}
+
+func ExampleParseSchedule() {
+	// 毎月最初の営業日の 10 時。2027 年 1 月は元日と土日を飛ばして 4 日になる。
+	s, err := jst.ParseSchedule("0 10 1B * *")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	next := s.Next(time.Date(2026, time.December, 1, 10, 0, 0, 0, jst.Location()))
+	fmt.Println(next.Format(time.RFC3339), jst.Format(next, jst.LayoutDisplay))
+	// Output: 2027-01-04T01:00:00Z 2027-01-04 10:00 JST
+}
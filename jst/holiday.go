@@ -0,0 +1,234 @@
+package jst
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// 祝日の判定範囲です。
+//
+// 「国民の祝日に関する法律」は 1948 年 7 月 20 日に施行されたため、それより前は祝日としません。
+// 春分の日・秋分の日は国立天文台の観測に基づき前年 2 月に官報で公表されるもので、ここでは
+// 近似式で求めます。近似式が使えるのは 2150 年までのため、それ以降の年は両日を判定しません。
+const (
+	holidayActYear = 1948
+	maxEquinoxYear = 2150
+)
+
+// 振替休日と国民の休日の、制度が始まった日と現行の規定に改められた日です。
+var (
+	substituteSince      = time.Date(1973, time.April, 12, 0, 0, 0, 0, time.UTC)
+	citizensHolidaySince = time.Date(1985, time.December, 27, 0, 0, 0, 0, time.UTC)
+	holidayAct2007       = time.Date(2007, time.January, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// Holiday は祝日 1 日分です。
+type Holiday struct {
+	// Date はその日の JST の 0 時です。
+	Date time.Time
+	// Name は祝日の名前です。振替休日は "振替休日"、祝日に挟まれた休日は "国民の休日" です。
+	Name string
+}
+
+// IsHoliday は、t を JST に変換した日付が祝日（振替休日・国民の休日を含む）かを返します。
+func IsHoliday(t time.Time) bool {
+	_, ok := HolidayName(t)
+	return ok
+}
+
+// HolidayName は、t を JST に変換した日付が祝日ならその名前を返します。
+//
+// 祝日は内閣府が公表する表を取り込まず、祝日法の規定から計算します。固定日の祝日、
+// ハッピーマンデー、春分・秋分の日の近似式、振替休日、国民の休日に加え、改元に伴う 2019 年の
+// 休日や、東京オリンピック・パラリンピックに伴う 2020 年・2021 年の移動にも対応しています。
+// 祝日法とは別の法律で定められた皇室行事の休日も含みます。
+func HolidayName(t time.Time) (string, bool) {
+	t = From(t)
+	name, ok := holidaysIn(t.Year()).names[monthDay{t.Month(), t.Day()}]
+	return name, ok
+}
+
+// Holidays は year 年の祝日を日付順に返します。
+func Holidays(year int) []Holiday {
+	return slices.Clone(holidaysIn(year).list)
+}
+
+// monthDay は年の中の日付です。
+type monthDay struct {
+	month time.Month
+	day   int
+}
+
+// holidayYear は 1 年分の祝日です。
+type holidayYear struct {
+	list  []Holiday
+	names map[monthDay]string
+}
+
+// holidayCache は年ごとの計算結果です。営業日の計算などで同じ年を何度も引くため、一度だけ計算します。
+var holidayCache sync.Map // map[int]*holidayYear
+
+func holidaysIn(year int) *holidayYear {
+	if cached, ok := holidayCache.Load(year); ok {
+		return cached.(*holidayYear)
+	}
+	cached, _ := holidayCache.LoadOrStore(year, computeHolidays(year))
+	return cached.(*holidayYear)
+}
+
+// holidayRule は祝日の規定 1 つ分です。from 年から to 年まで（to が 0 なら現在まで）適用します。
+type holidayRule struct {
+	name     string
+	from, to int
+	month    time.Month
+	day      func(year int, month time.Month) int
+}
+
+func fixed(day int) func(int, time.Month) int {
+	return func(int, time.Month) int { return day }
+}
+
+// happyMonday は、その月の n 回目の月曜日を返す規定です。
+func happyMonday(n int) func(int, time.Month) int {
+	return func(year int, month time.Month) int {
+		first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC).Weekday()
+		return 1 + (int(time.Monday)-int(first)+7)%7 + (n-1)*7
+	}
+}
+
+// holidayRules は祝日法と、皇室行事などの休日を定めた法律の規定です。
+var holidayRules = []holidayRule{
+	{"元日", 1949, 0, time.January, fixed(1)},
+	{"成人の日", 1949, 1999, time.January, fixed(15)},
+	{"成人の日", 2000, 0, time.January, happyMonday(2)},
+	{"建国記念の日", 1967, 0, time.February, fixed(11)},
+	{"天皇誕生日", 2020, 0, time.February, fixed(23)},
+	{"春分の日", 1949, maxEquinoxYear, time.March, vernalEquinox},
+	{"天皇誕生日", 1949, 1988, time.April, fixed(29)},
+	{"みどりの日", 1989, 2006, time.April, fixed(29)},
+	{"昭和の日", 2007, 0, time.April, fixed(29)},
+	{"憲法記念日", 1949, 0, time.May, fixed(3)},
+	{"みどりの日", 2007, 0, time.May, fixed(4)},
+	{"こどもの日", 1949, 0, time.May, fixed(5)},
+	{"海の日", 1996, 2002, time.July, fixed(20)},
+	{"海の日", 2003, 2019, time.July, happyMonday(3)},
+	{"海の日", 2020, 2020, time.July, fixed(23)},
+	{"海の日", 2021, 2021, time.July, fixed(22)},
+	{"海の日", 2022, 0, time.July, happyMonday(3)},
+	{"スポーツの日", 2020, 2020, time.July, fixed(24)},
+	{"スポーツの日", 2021, 2021, time.July, fixed(23)},
+	{"山の日", 2016, 2019, time.August, fixed(11)},
+	{"山の日", 2020, 2020, time.August, fixed(10)},
+	{"山の日", 2021, 2021, time.August, fixed(8)},
+	{"山の日", 2022, 0, time.August, fixed(11)},
+	{"敬老の日", 1966, 2002, time.September, fixed(15)},
+	{"敬老の日", 2003, 0, time.September, happyMonday(3)},
+	{"秋分の日", 1948, maxEquinoxYear, time.September, autumnalEquinox},
+	{"体育の日", 1966, 1999, time.October, fixed(10)},
+	{"体育の日", 2000, 2019, time.October, happyMonday(2)},
+	{"スポーツの日", 2022, 0, time.October, happyMonday(2)},
+	{"文化の日", 1948, 0, time.November, fixed(3)},
+	{"勤労感謝の日", 1948, 0, time.November, fixed(23)},
+	{"天皇誕生日", 1989, 2018, time.December, fixed(23)},
+
+	{"皇太子明仁親王の結婚の儀", 1959, 1959, time.April, fixed(10)},
+	{"昭和天皇の大喪の礼", 1989, 1989, time.February, fixed(24)},
+	{"即位礼正殿の儀", 1990, 1990, time.November, fixed(12)},
+	{"皇太子徳仁親王の結婚の儀", 1993, 1993, time.June, fixed(9)},
+	{"天皇の即位の日", 2019, 2019, time.May, fixed(1)},
+	{"即位礼正殿の儀", 2019, 2019, time.October, fixed(22)},
+}
+
+// vernalEquinox は春分の日の近似式です。
+func vernalEquinox(year int, _ time.Month) int {
+	switch {
+	case year < 1980:
+		return equinoxDay(20.8357, year, 1983)
+	case year < 2100:
+		return equinoxDay(20.8431, year, 1980)
+	default:
+		return equinoxDay(21.8510, year, 1980)
+	}
+}
+
+// autumnalEquinox は秋分の日の近似式です。
+func autumnalEquinox(year int, _ time.Month) int {
+	switch {
+	case year < 1980:
+		return equinoxDay(23.2588, year, 1983)
+	case year < 2100:
+		return equinoxDay(23.2488, year, 1980)
+	default:
+		return equinoxDay(24.2488, year, 1980)
+	}
+}
+
+// equinoxDay は近似式で日を求めます。閏年の補正は公表された式と同じく 0 方向への切り捨てで、
+// leapBase より前の年で負の値を切り下げると 1 日ずれます。
+func equinoxDay(base float64, year, leapBase int) int {
+	return int(base + 0.242194*float64(year-1980) - float64((year-leapBase)/4))
+}
+
+// computeHolidays は year 年の祝日を規定から計算します。
+func computeHolidays(year int) *holidayYear {
+	names := map[monthDay]string{}
+	if year < holidayActYear {
+		return &holidayYear{names: names}
+	}
+
+	national := map[time.Time]string{}
+	for _, r := range holidayRules {
+		if year < r.from || (r.to != 0 && year > r.to) {
+			continue
+		}
+		national[time.Date(year, r.month, r.day(year, r.month), 0, 0, 0, 0, time.UTC)] = r.name
+	}
+
+	extra := map[time.Time]string{}
+	for d := range national {
+		if d.Weekday() != time.Sunday || d.Before(substituteSince) {
+			continue
+		}
+		// 2006 年までは翌月曜日だけが振替休日で、月曜日が祝日なら振り替えませんでした。
+		// 2007 年からは、その後の最も近い祝日でない日に振り替えます。
+		next := d.AddDate(0, 0, 1)
+		if !d.Before(holidayAct2007) {
+			for national[next] != "" {
+				next = next.AddDate(0, 0, 1)
+			}
+		}
+		if national[next] == "" {
+			extra[next] = "振替休日"
+		}
+	}
+	for d := range national {
+		// 祝日に挟まれた祝日でない日は休日です。2006 年までは日曜日と振替休日を除きました。
+		between := d.AddDate(0, 0, 1)
+		if between.Before(citizensHolidaySince) || national[between] != "" || national[between.AddDate(0, 0, 1)] == "" {
+			continue
+		}
+		if between.Before(holidayAct2007) && (between.Weekday() == time.Sunday || extra[between] != "") {
+			continue
+		}
+		if extra[between] == "" {
+			extra[between] = "国民の休日"
+		}
+	}
+
+	list := make([]Holiday, 0, len(national)+len(extra))
+	for _, days := range []map[time.Time]string{national, extra} {
+		for d, name := range days {
+			if d.Year() != year {
+				continue
+			}
+			names[monthDay{d.Month(), d.Day()}] = name
+			list = append(list, Holiday{
+				Date: time.Date(year, d.Month(), d.Day(), 0, 0, 0, 0, Location()),
+				Name: name,
+			})
+		}
+	}
+	slices.SortFunc(list, func(a, b Holiday) int { return a.Date.Compare(b.Date) })
+	return &holidayYear{list: list, names: names}
+}
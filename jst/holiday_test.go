@@ -0,0 +1,134 @@
+package jst_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shouni/go-utils/jst"
+)
+
+// publishedHolidays は内閣府「国民の祝日について」の祝日一覧から転記した日付です。
+// 一覧では振替休日と国民の休日をどちらも「休日」と表記しています。
+var publishedHolidays = map[int][]string{
+	1955: {"01-01", "01-15", "03-21", "04-29", "05-03", "05-05", "09-24", "11-03", "11-23"},
+	1960: {"01-01", "01-15", "03-20", "04-29", "05-03", "05-05", "09-23", "11-03", "11-23"},
+	1976: {
+		"01-01", "01-15", "02-11", "03-20", "04-29", "05-03", "05-05",
+		"09-15", "09-23", "10-10", "10-11", "11-03", "11-23",
+	},
+	2019: {
+		"01-01", "01-14", "02-11", "03-21", "04-29", "04-30", "05-01", "05-02", "05-03", "05-04", "05-05", "05-06",
+		"07-15", "08-11", "08-12", "09-16", "09-23", "10-14", "10-22", "11-03", "11-04", "11-23",
+	},
+	2020: {
+		"01-01", "01-13", "02-11", "02-23", "02-24", "03-20", "04-29", "05-03", "05-04", "05-05", "05-06",
+		"07-23", "07-24", "08-10", "09-21", "09-22", "11-03", "11-23",
+	},
+	2021: {
+		"01-01", "01-11", "02-11", "02-23", "03-20", "04-29", "05-03", "05-04", "05-05",
+		"07-22", "07-23", "08-08", "08-09", "09-20", "09-23", "11-03", "11-23",
+	},
+	2024: {
+		"01-01", "01-08", "02-11", "02-12", "02-23", "03-20", "04-29", "05-03", "05-04", "05-05", "05-06",
+		"07-15", "08-11", "08-12", "09-16", "09-22", "09-23", "10-14", "11-03", "11-04", "11-23",
+	},
+	2025: {
+		"01-01", "01-13", "02-11", "02-23", "02-24", "03-20", "04-29", "05-03", "05-04", "05-05", "05-06",
+		"07-21", "08-11", "09-15", "09-23", "10-13", "11-03", "11-23", "11-24",
+	},
+}
+
+// TestHolidaysMatchPublishedTable は、計算した祝日が公表された一覧と一致することを確認します。
+func TestHolidaysMatchPublishedTable(t *testing.T) {
+	for year, want := range publishedHolidays {
+		got := jst.Holidays(year)
+		var dates []string
+		for _, h := range got {
+			dates = append(dates, h.Date.Format("01-02"))
+		}
+		if len(dates) != len(want) {
+			t.Errorf("Holidays(%d) = %v, want %v", year, dates, want)
+			continue
+		}
+		for i := range want {
+			if dates[i] != want[i] {
+				t.Errorf("Holidays(%d) = %v, want %v", year, dates, want)
+				break
+			}
+		}
+	}
+}
+
+// TestHolidayName は、制度の変遷に関わる日付の判定を確認します。
+func TestHolidayName(t *testing.T) {
+	tests := []struct {
+		date string
+		want string // 空なら祝日でない
+	}{
+		{"1948-07-19", ""},     // 祝日法の施行前
+		{"1948-09-23", "秋分の日"}, // 1983 年より前の年の閏年の補正は 0 方向に切り捨てる
+		{"1948-09-24", ""},
+		{"1948-11-03", "文化の日"},
+		{"1959-04-10", "皇太子明仁親王の結婚の儀"},
+		{"1973-04-30", "振替休日"}, // 振替休日の初適用
+		{"1979-09-24", "秋分の日"},
+		{"1988-05-04", "国民の休日"},
+		{"1989-02-24", "昭和天皇の大喪の礼"},
+		{"1989-04-29", "みどりの日"},
+		{"1998-05-04", "振替休日"}, // 2006 年までは憲法記念日の振替が国民の休日より優先
+		{"1999-10-11", "振替休日"},
+		{"2000-01-10", "成人の日"},
+		{"2003-05-04", ""}, // 2006 年までは日曜日を国民の休日にしない
+		{"2003-05-06", ""},
+		{"2008-05-06", "振替休日"}, // 2007 年からは祝日を飛ばして振り替える
+		{"2009-09-22", "国民の休日"},
+		{"2018-12-23", "天皇誕生日"},
+		{"2019-05-01", "天皇の即位の日"},
+		{"2019-04-30", "国民の休日"},
+		{"2019-12-23", ""},
+		{"2020-07-24", "スポーツの日"},
+		{"2021-08-09", "振替休日"},
+		{"2026-09-22", "国民の休日"},
+		{"2026-03-20", "春分の日"},
+		{"2026-09-23", "秋分の日"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.date, func(t *testing.T) {
+			d, err := time.ParseInLocation("2006-01-02", tt.date, jst.Location())
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, ok := jst.HolidayName(d)
+			if got != tt.want || ok != (tt.want != "") {
+				t.Errorf("HolidayName(%s) = %q, %v, want %q", tt.date, got, ok, tt.want)
+			}
+			if jst.IsHoliday(d) != ok {
+				t.Errorf("IsHoliday(%s) と HolidayName の結果が一致しない", tt.date)
+			}
+		})
+	}
+}
+
+// TestIsHolidayConvertsToJST は、UTC の時刻を JST の日付で判定することを確認します。
+func TestIsHolidayConvertsToJST(t *testing.T) {
+	// 2024-12-31T15:00Z は JST で 2025-01-01 00:00。
+	if !jst.IsHoliday(time.Date(2024, time.December, 31, 15, 0, 0, 0, time.UTC)) {
+		t.Error("JST で元日になる UTC の時刻が祝日と判定されない")
+	}
+	if jst.IsHoliday(time.Date(2024, time.December, 31, 14, 59, 0, 0, time.UTC)) {
+		t.Error("JST で大晦日になる UTC の時刻が祝日と判定された")
+	}
+}
+
+// TestHolidaysReturnsCopy は、戻り値を書き換えても次の呼び出しに影響しないことを確認します。
+func TestHolidaysReturnsCopy(t *testing.T) {
+	first := jst.Holidays(2025)
+	first[0].Name = "changed"
+	if got := jst.Holidays(2025)[0].Name; got != "元日" {
+		t.Errorf("Holidays(2025)[0].Name = %q, want 元日", got)
+	}
+	if got := jst.Holidays(1900); len(got) != 0 {
+		t.Errorf("Holidays(1900) = %v, want 空", got)
+	}
+}
@@ -0,0 +1,189 @@
+package jst
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FormatRelative の既定の閾値です。
+const (
+	// DefaultJustNow は、"たった今" / "まもなく" と表す差の上限です。
+	DefaultJustNow = time.Minute
+
+	// DefaultRelativeMaxDays は、"N日前" / "N日後" と表す日数の上限です。
+	DefaultRelativeMaxDays = 7
+)
+
+// RelativeOptions は FormatRelative の設定です。ゼロ値のままでも使えます。
+type RelativeOptions struct {
+	// JustNow は "たった今" / "まもなく" と表す差の上限です。0 以下なら DefaultJustNow です。
+	JustNow time.Duration
+
+	// MaxDays は "N日前" / "N日後" と表す日数の上限です。これより離れた日時は Layout で表します。
+	// 0 以下なら DefaultRelativeMaxDays です。
+	MaxDays int
+
+	// Layout は MaxDays より離れた日時を表すレイアウトです。FormatJapanese と同じ書式で、
+	// 空なら LayoutDisplay です。
+	Layout string
+}
+
+// FormatRelative は、now を基準に t を "3分前"、"昨日 15:04"、"2日後" のように表します。
+//
+// 表し方は差の大きさで次のように切り替わります。"昨日" / "今日" / "明日" は 24 時間の差ではなく
+// JST の日付で判定するため、0 時 30 分から見た前日の 23 時は 1 時間半前でも "昨日 23:00" です。
+//
+//	JustNow 未満                   たった今 / まもなく
+//	1 時間未満                     N分前 / N分後
+//	同じ日付                       N時間前 / N時間後
+//	前日 / 翌日                    昨日 15:04 / 明日 15:04
+//	MaxDays 日以内                 N日前 / N日後
+//	それより離れた日時             Layout で整形した日時
+func FormatRelative(t, now time.Time, opts RelativeOptions) string {
+	justNow := opts.JustNow
+	if justNow <= 0 {
+		justNow = DefaultJustNow
+	}
+	maxDays := opts.MaxDays
+	if maxDays <= 0 {
+		maxDays = DefaultRelativeMaxDays
+	}
+
+	diff := t.Sub(now)
+	future := diff > 0
+	if diff < 0 {
+		diff = -diff
+	}
+	suffix := "前"
+	if future {
+		suffix = "後"
+	}
+
+	days := DateOf(t).Sub(DateOf(now))
+	switch {
+	case diff < justNow:
+		if future {
+			return "まもなく"
+		}
+		return "たった今"
+	case diff < time.Hour:
+		return strconv.Itoa(int(diff/time.Minute)) + "分" + suffix
+	case days == 0:
+		return strconv.Itoa(int(diff/time.Hour)) + "時間" + suffix
+	case days == -1:
+		return "昨日 " + Format(t, "15:04")
+	case days == 1:
+		return "明日 " + Format(t, "15:04")
+	case -maxDays <= days && days <= maxDays:
+		return strconv.Itoa(max(days, -days)) + "日" + suffix
+	}
+
+	layout := opts.Layout
+	if layout == "" {
+		layout = LayoutDisplay
+	}
+	return FormatJapanese(t, layout)
+}
+
+// Rounding は FormatDuration で最小の単位に満たない端数の扱いです。
+type Rounding int
+
+const (
+	// RoundDown は端数を切り捨てます。経過時間の表示で、まだ達していない値を出さないために使います。
+	RoundDown Rounding = iota
+	// RoundHalfUp は端数を四捨五入します。
+	RoundHalfUp
+	// RoundUp は端数を切り上げます。残り時間の表示で、実際より短く見せないために使います。
+	RoundUp
+)
+
+// DurationOptions は FormatDuration の設定です。ゼロ値は、最大 2 つの単位を秒まで切り捨てで表します。
+type DurationOptions struct {
+	// MaxUnits は表す単位の数の上限です。0 以下なら 2 で、"1時間23分45秒" は "1時間23分" になります。
+	MaxUnits int
+
+	// Precision は最小の単位です。24*time.Hour、time.Hour、time.Minute、time.Second のいずれかで、
+	// それ以外の値はそれ以下で最大の単位とみなします。0 以下なら time.Second です。
+	Precision time.Duration
+
+	// Rounding は最小の単位に満たない端数の扱いです。
+	Rounding Rounding
+}
+
+// durationUnit は FormatDuration で使う単位です。
+type durationUnit struct {
+	size time.Duration
+	name string
+}
+
+var durationUnits = []durationUnit{
+	{24 * time.Hour, "日"},
+	{time.Hour, "時間"},
+	{time.Minute, "分"},
+	{time.Second, "秒"},
+}
+
+// FormatDuration は d を "1時間23分" のように日本語で表します。
+//
+// 値が 0 の単位は省き、すべて 0 なら最小の単位で "0分" のように表します。負の値は先頭に "-" を付けます。
+func FormatDuration(d time.Duration, opts DurationOptions) string {
+	maxUnits := opts.MaxUnits
+	if maxUnits <= 0 {
+		maxUnits = 2
+	}
+	smallest := len(durationUnits) - 1
+	for i, u := range durationUnits {
+		if opts.Precision >= u.size {
+			smallest = i
+			break
+		}
+	}
+
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+
+	// 先頭の単位から数えて MaxUnits 個目か Precision の、大きい方を最小の単位にして丸めます。
+	leading := smallest
+	for i := range durationUnits[:smallest] {
+		if d >= durationUnits[i].size {
+			leading = i
+			break
+		}
+	}
+	last := min(smallest, leading+maxUnits-1)
+	d = roundDuration(d, durationUnits[last].size, opts.Rounding)
+
+	var b strings.Builder
+	b.WriteString(sign)
+	for _, u := range durationUnits[:last+1] {
+		if n := d / u.size; n > 0 {
+			b.WriteString(strconv.FormatInt(int64(n), 10))
+			b.WriteString(u.name)
+			d -= n * u.size
+		}
+	}
+	if b.Len() == len(sign) {
+		return "0" + durationUnits[last].name
+	}
+	return b.String()
+}
+
+// roundDuration は d を unit の倍数に丸めます。
+func roundDuration(d, unit time.Duration, mode Rounding) time.Duration {
+	rem := d % unit
+	if rem == 0 {
+		return d
+	}
+	switch mode {
+	case RoundHalfUp:
+		if rem*2 >= unit {
+			return d - rem + unit
+		}
+	case RoundUp:
+		return d - rem + unit
+	}
+	return d - rem
+}
@@ -0,0 +1,71 @@
+package jst_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shouni/go-utils/jst"
+)
+
+func TestFormatRelative(t *testing.T) {
+	now := time.Date(2026, time.July, 25, 0, 30, 0, 0, jst.Location())
+	tests := []struct {
+		name string
+		t    time.Time
+		opts jst.RelativeOptions
+		want string
+	}{
+		{"直前", now.Add(-30 * time.Second), jst.RelativeOptions{}, "たった今"},
+		{"直後", now.Add(30 * time.Second), jst.RelativeOptions{}, "まもなく"},
+		{"分", now.Add(-3 * time.Minute), jst.RelativeOptions{}, "3分前"},
+		{"分（日付をまたぐ）", now.Add(-40 * time.Minute), jst.RelativeOptions{}, "40分前"},
+		{"時間", now.Add(5 * time.Hour), jst.RelativeOptions{}, "5時間後"},
+		// 90 分前でも日付が前日なら "昨日"。
+		{"昨日", now.Add(-90 * time.Minute), jst.RelativeOptions{}, "昨日 23:00"},
+		{"明日", now.Add(30 * time.Hour), jst.RelativeOptions{}, "明日 06:30"},
+		{"日前", now.AddDate(0, 0, -3), jst.RelativeOptions{}, "3日前"},
+		{"日後", now.AddDate(0, 0, 7), jst.RelativeOptions{}, "7日後"},
+		{"絶対表記", now.AddDate(0, 0, -8), jst.RelativeOptions{}, "2026-07-17 00:30 JST"},
+		{"MaxDays", now.AddDate(0, 0, -3), jst.RelativeOptions{MaxDays: 2}, "2026-07-22 00:30 JST"},
+		{"Layout", now.AddDate(0, 0, 10), jst.RelativeOptions{Layout: jst.LayoutJapaneseDate}, "2026年8月4日(火)"},
+		{"JustNow", now.Add(-3 * time.Minute), jst.RelativeOptions{JustNow: 5 * time.Minute}, "たった今"},
+		// UTC で渡しても JST の日付で判定する。
+		{"UTC", time.Date(2026, time.July, 24, 14, 0, 0, 0, time.UTC), jst.RelativeOptions{}, "昨日 23:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jst.FormatRelative(tt.t, now, tt.opts); got != tt.want {
+				t.Errorf("FormatRelative(%v) = %q, want %q", tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		opts jst.DurationOptions
+		want string
+	}{
+		{83 * time.Minute, jst.DurationOptions{}, "1時間23分"},
+		{83*time.Minute + 45*time.Second, jst.DurationOptions{}, "1時間23分"},
+		{83*time.Minute + 45*time.Second, jst.DurationOptions{MaxUnits: 3}, "1時間23分45秒"},
+		{83*time.Minute + 45*time.Second, jst.DurationOptions{Rounding: jst.RoundHalfUp}, "1時間24分"},
+		{83*time.Minute + 1*time.Second, jst.DurationOptions{Rounding: jst.RoundUp}, "1時間24分"},
+		{59*time.Minute + 40*time.Second, jst.DurationOptions{MaxUnits: 1, Rounding: jst.RoundHalfUp}, "1時間"},
+		{26*time.Hour + 5*time.Minute, jst.DurationOptions{}, "1日2時間"},
+		{24*time.Hour + 5*time.Minute, jst.DurationOptions{MaxUnits: 3}, "1日5分"},
+		{45 * time.Second, jst.DurationOptions{}, "45秒"},
+		{45 * time.Second, jst.DurationOptions{Precision: time.Minute}, "0分"},
+		{45 * time.Second, jst.DurationOptions{Precision: time.Minute, Rounding: jst.RoundUp}, "1分"},
+		{90 * time.Minute, jst.DurationOptions{Precision: time.Hour, Rounding: jst.RoundHalfUp}, "2時間"},
+		{1500 * time.Millisecond, jst.DurationOptions{}, "1秒"},
+		{0, jst.DurationOptions{}, "0秒"},
+		{-5 * time.Minute, jst.DurationOptions{}, "-5分"},
+	}
+	for _, tt := range tests {
+		if got := jst.FormatDuration(tt.d, tt.opts); got != tt.want {
+			t.Errorf("FormatDuration(%v, %+v) = %q, want %q", tt.d, tt.opts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package jst
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 日本語の表示に使うレイアウト。FormatJapanese / ParseJapanese と組み合わせて使います。
+const (
+	// LayoutJapaneseDate は "2026年7月25日(土)" の形式です。
+	LayoutJapaneseDate = "2006年1月2日(Mon)"
+
+	// LayoutJapaneseDateTime は "2026年7月25日(土) 15:04" の形式です。
+	LayoutJapaneseDateTime = "2006年1月2日(Mon) 15:04"
+
+	// LayoutJapaneseTime は "午後3時4分" の形式です。
+	LayoutJapaneseTime = "PM3時4分"
+)
+
+var (
+	weekdayShortNames = [...]string{"日", "月", "火", "水", "木", "金", "土"}
+	weekdayLongNames  = [...]string{"日曜日", "月曜日", "火曜日", "水曜日", "木曜日", "金曜日", "土曜日"}
+)
+
+// japaneseToken は、time のレイアウトのうち日本語に置き換える要素です。
+type japaneseToken int
+
+const (
+	tokenNone japaneseToken = iota
+	tokenWeekdayShort
+	tokenWeekdayLong
+	tokenAMPM
+)
+
+// layoutChunk はレイアウトを日本語に置き換える要素で区切った断片です。
+type layoutChunk struct {
+	layout string // token が tokenNone のときの、time のレイアウトの断片
+	token  japaneseToken
+}
+
+// splitJapaneseLayout は layout を、日本語に置き換える要素とそれ以外に分けます。
+func splitJapaneseLayout(layout string) []layoutChunk {
+	var chunks []layoutChunk
+	start := 0
+	for i := 0; i < len(layout); {
+		var token japaneseToken
+		var width int
+		switch rest := layout[i:]; {
+		case strings.HasPrefix(rest, "Monday"):
+			token, width = tokenWeekdayLong, len("Monday")
+		case strings.HasPrefix(rest, "Mon"):
+			token, width = tokenWeekdayShort, len("Mon")
+		case strings.HasPrefix(rest, "PM"), strings.HasPrefix(rest, "pm"):
+			token, width = tokenAMPM, len("PM")
+		default:
+			i++
+			continue
+		}
+		if start < i {
+			chunks = append(chunks, layoutChunk{layout: layout[start:i]})
+		}
+		chunks = append(chunks, layoutChunk{token: token})
+		i += width
+		start = i
+	}
+	if start < len(layout) {
+		chunks = append(chunks, layoutChunk{layout: layout[start:]})
+	}
+	return chunks
+}
+
+// FormatJapanese は、t を JST に変換したうえで、曜日と午前・午後を日本語で表して整形します。
+//
+// layout は time.Format と同じ書式で、次の要素だけを日本語に置き換えます。
+// "2006年1月2日" のような漢字の単位は time.Format のままで書けます。
+//
+//	"Mon"        → "土"
+//	"Monday"     → "土曜日"
+//	"PM" / "pm"  → "午前" / "午後"
+//
+// 12 時間制の時は time.Format と同じく 1〜12 で表します（正午は "午後12時"）。
+func FormatJapanese(t time.Time, layout string) string {
+	t = From(t)
+	var b strings.Builder
+	for _, c := range splitJapaneseLayout(layout) {
+		switch c.token {
+		case tokenWeekdayShort:
+			b.WriteString(weekdayShortNames[t.Weekday()])
+		case tokenWeekdayLong:
+			b.WriteString(weekdayLongNames[t.Weekday()])
+		case tokenAMPM:
+			if t.Hour() < 12 {
+				b.WriteString("午前")
+			} else {
+				b.WriteString("午後")
+			}
+		default:
+			b.WriteString(t.Format(c.layout))
+		}
+	}
+	return b.String()
+}
+
+// ParseJapanese は、FormatJapanese と同じレイアウトで整形された文字列を JST として解釈します。
+//
+// 曜日を含むレイアウトでは、曜日が日付と合っているかも確かめます。
+func ParseJapanese(value, layout string) (time.Time, error) {
+	var p japaneseParser
+	var en strings.Builder
+	for _, c := range splitJapaneseLayout(layout) {
+		switch c.token {
+		case tokenWeekdayShort:
+			en.WriteString("Mon")
+		case tokenWeekdayLong:
+			en.WriteString("Monday")
+		case tokenAMPM:
+			en.WriteString("PM")
+		default:
+			en.WriteString(c.layout)
+		}
+		if c.token != tokenNone {
+			p.tokens = append(p.tokens, c.token)
+		}
+	}
+	p.layout = en.String()
+
+	t, ok := p.parse(value, 0, 0, nil)
+	if !ok {
+		return time.Time{}, fmt.Errorf("parse time %q as %q: %w", value, layout, p.err)
+	}
+	return t, nil
+}
+
+// japaneseParser は、入力の日本語の要素を英語へ置き換えて time.ParseInLocation に渡します。
+//
+// "2026年7月26日(日)" の "日" のように、曜日の名前は日付の単位と区別できないことがあります。
+// そのため、要素ごとに置き換える位置の候補を順に試し、解釈できた最初のものを採用します。
+type japaneseParser struct {
+	tokens []japaneseToken
+	layout string
+	err    error
+}
+
+func (p *japaneseParser) parse(input string, from, k int, weekdays []time.Weekday) (time.Time, bool) {
+	if k == len(p.tokens) {
+		t, err := time.ParseInLocation(p.layout, input, Location())
+		if err != nil {
+			p.err = err
+			return time.Time{}, false
+		}
+		for _, w := range weekdays {
+			if t.Weekday() != w {
+				p.err = fmt.Errorf("%w: %s is %s", errWeekdayMismatch, t.Format(time.DateOnly), weekdayLongNames[t.Weekday()])
+				return time.Time{}, false
+			}
+		}
+		return t, true
+	}
+
+	names, english := p.candidates(p.tokens[k])
+	for i := from; i < len(input); i++ {
+		for j, name := range names {
+			if !strings.HasPrefix(input[i:], name) {
+				continue
+			}
+			replaced := input[:i] + english[j] + input[i+len(name):]
+			next := weekdays
+			if p.tokens[k] != tokenAMPM {
+				next = append(weekdays[:len(weekdays):len(weekdays)], time.Weekday(j))
+			}
+			if t, ok := p.parse(replaced, i+len(english[j]), k+1, next); ok {
+				return t, true
+			}
+		}
+	}
+	if p.err == nil {
+		p.err = errJapaneseTokenMissing
+	}
+	return time.Time{}, false
+}
+
+// candidates は要素の日本語の名前と、対応する英語の名前を返します。
+func (p *japaneseParser) candidates(token japaneseToken) (names, english []string) {
+	switch token {
+	case tokenWeekdayShort:
+		return weekdayShortNames[:], []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+	case tokenWeekdayLong:
+		return weekdayLongNames[:], []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+	default:
+		return []string{"午前", "午後"}, []string{"AM", "PM"}
+	}
+}
+
+var (
+	errWeekdayMismatch      = errors.New("weekday does not match the date")
+	errJapaneseTokenMissing = errors.New("weekday or AM/PM not found")
+)
@@ -0,0 +1,76 @@
+package jst_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shouni/go-utils/jst"
+)
+
+func TestFormatJapanese(t *testing.T) {
+	afternoon := time.Date(2026, time.July, 25, 15, 4, 0, 0, jst.Location())
+	tests := []struct {
+		t      time.Time
+		layout string
+		want   string
+	}{
+		{afternoon, jst.LayoutJapaneseDate, "2026年7月25日(土)"},
+		{afternoon, jst.LayoutJapaneseDateTime, "2026年7月25日(土) 15:04"},
+		{afternoon, jst.LayoutJapaneseTime, "午後3時4分"},
+		{afternoon, "2006年01月02日 Monday", "2026年07月25日 土曜日"},
+		{time.Date(2026, time.July, 26, 9, 5, 0, 0, jst.Location()), "1/2(Mon) pm3:04", "7/26(日) 午前9:05"},
+		// UTC の時刻は JST に変換してから整形する。
+		{time.Date(2026, time.July, 25, 15, 30, 0, 0, time.UTC), jst.LayoutJapaneseDateTime, "2026年7月26日(日) 00:30"},
+	}
+	for _, tt := range tests {
+		if got := jst.FormatJapanese(tt.t, tt.layout); got != tt.want {
+			t.Errorf("FormatJapanese(%v, %q) = %q, want %q", tt.t, tt.layout, got, tt.want)
+		}
+	}
+}
+
+// TestJapaneseRoundTrip は、曜日の "日" と日付の単位の "日" が並んでも往復できることを確認します。
+func TestJapaneseRoundTrip(t *testing.T) {
+	layouts := []string{
+		jst.LayoutJapaneseDate,
+		jst.LayoutJapaneseDateTime,
+		"2006年1月2日Mon曜日 PM3時4分",
+		"2006年1月2日 Monday",
+	}
+	for day := 19; day <= 26; day++ {
+		for _, hour := range []int{0, 11, 12, 23} {
+			want := time.Date(2026, time.July, day, hour, 4, 0, 0, jst.Location())
+			for _, layout := range layouts {
+				s := jst.FormatJapanese(want, layout)
+				got, err := jst.ParseJapanese(s, layout)
+				if err != nil {
+					t.Fatalf("ParseJapanese(%q, %q) error = %v", s, layout, err)
+				}
+				if again := jst.FormatJapanese(got, layout); again != s || got.Location() != jst.Location() {
+					t.Errorf("ParseJapanese(%q, %q) = %v, 再整形すると %q", s, layout, got, again)
+				}
+			}
+		}
+	}
+
+	got, err := jst.ParseJapanese("午後3時4分", jst.LayoutJapaneseTime)
+	if err != nil || got.Hour() != 15 || got.Minute() != 4 {
+		t.Errorf("ParseJapanese(午後3時4分) = %v, %v, want 15:04", got, err)
+	}
+}
+
+func TestParseJapaneseErrors(t *testing.T) {
+	tests := []struct {
+		value, layout string
+	}{
+		{"2026年7月25日(日)", jst.LayoutJapaneseDate}, // 曜日が日付と合わない
+		{"2026年7月25日", jst.LayoutJapaneseDate},    // 曜日が無い
+		{"夕方3時4分", jst.LayoutJapaneseTime},
+		{"2026年13月1日(月)", jst.LayoutJapaneseDate},
+	}
+	for _, tt := range tests {
+		if got, err := jst.ParseJapanese(tt.value, tt.layout); err == nil {
+			t.Errorf("ParseJapanese(%q, %q) = %v, want error", tt.value, tt.layout, got)
+		}
+	}
+}
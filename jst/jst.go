@@ -62,8 +62,10 @@ func loadLocationOrFallback(name string) *time.Location {
 
 // Now は、日本標準時 (JST) における現在の時刻を返します。
 // 例: 2025-11-23 15:00:00 +0900 JST
+//
+// テストで時刻を固定したい処理では、Clock を受け取って NowFrom を使ってください。
 func Now() time.Time {
-	return time.Now().In(Location())
+	return NowFrom(SystemClock)
 }
 
 // From は、引数として渡された time.Time を JST に変換します。
@@ -0,0 +1,70 @@
+package jst
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrNoMatchingLayout は、ParseAny がどのレイアウトでも解釈できなかったことを表します。
+var ErrNoMatchingLayout = errors.New("no matching layout")
+
+// DefaultParseLayouts は、ParseAny が既定で試すレイアウトを試す順に返します。
+// 戻り値は毎回新しいスライスなので、独自のレイアウトを足して ParseAny に渡せます。
+//
+// 月・日・時は "1"、"2"、"15" の書式で、"7" と "07" のどちらも受け付けます。
+func DefaultParseLayouts() []string {
+	return []string{
+		time.RFC3339,
+		LayoutTimestamp,
+		LayoutDisplay,
+		"2006-1-2T15:04:05",
+		"2006-1-2 15:04:05",
+		"2006-1-2 15:04",
+		"2006/1/2 15:04:05",
+		"2006/1/2 15:04",
+		"2006-1-2",
+		"2006/1/2",
+		"20060102150405",
+		"20060102",
+		LayoutJapaneseDateTime,
+		"2006年1月2日 15:04:05",
+		"2006年1月2日 15:04",
+		"2006年1月2日 15時4分",
+		LayoutJapaneseDate,
+		"2006年1月2日",
+	}
+}
+
+// ParseAny は、書式の分からない利用者の入力や古いデータを、layouts を順に試して解釈します。
+// layouts を省略すると DefaultParseLayouts を使います。戻り値の 2 つ目は解釈できたレイアウトです。
+//
+// 全角の数字・記号・空白は半角にしてから解釈し、前後の空白は無視します。レイアウトは
+// FormatJapanese と同じ書式で、曜日や午前・午後を日本語で書いた入力も解釈できます。
+//
+// Parse と同じく、タイムゾーンを含まない値は time.Local によらず JST として解釈します。
+// "+09:00" などのオフセットを含む値はその時刻として解釈し、いずれの場合も JST に変換して返します。
+// タイムゾーンの略称は "JST"、"UTC"、"GMT" だけを受け付け、"PST" のような値はそのレイアウトに
+// 一致しないものとします。
+func ParseAny(value string, layouts ...string) (time.Time, string, error) {
+	if len(layouts) == 0 {
+		layouts = DefaultParseLayouts()
+	}
+	normalized := strings.TrimSpace(toHalfWidth(value))
+	for _, layout := range layouts {
+		t, err := ParseJapanese(normalized, layout)
+		if err != nil {
+			continue
+		}
+		// time.Parse は知らないタイムゾーンの略称をオフセット 0 として受け付けるため、
+		// "PST" などの値が誤った時刻になります。オフセットが確かな略称だけを受け付けます。
+		if strings.Contains(layout, "MST") {
+			if name, _ := t.Zone(); name != "JST" && name != "UTC" && name != "GMT" {
+				continue
+			}
+		}
+		return From(t), layout, nil
+	}
+	return time.Time{}, "", fmt.Errorf("parse time %q: %w", value, ErrNoMatchingLayout)
+}
@@ -0,0 +1,81 @@
+package jst_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shouni/go-utils/jst"
+)
+
+func TestParseAny(t *testing.T) {
+	// ホストのタイムゾーンに依存しないことを確かめるため、time.Local を JST 以外にする。
+	prev := time.Local
+	time.Local = time.FixedZone("PST", -8*60*60)
+	t.Cleanup(func() { time.Local = prev })
+
+	day := time.Date(2026, time.July, 25, 0, 0, 0, 0, jst.Location())
+	at := time.Date(2026, time.July, 25, 15, 4, 0, 0, jst.Location())
+	tests := []struct {
+		value      string
+		want       time.Time
+		wantLayout string
+	}{
+		{"2026/7/25", day, "2006/1/2"},
+		{"2026/07/25", day, "2006/1/2"},
+		{"2026-07-25 15:04", at, "2006-1-2 15:04"},
+		{"2026-07-25T15:04:00", at, "2006-1-2T15:04:05"},
+		{"20260725", day, "20060102"},
+		{"20260725150400", at, "20060102150405"},
+		{"2026年7月25日", day, "2006年1月2日"},
+		{"2026年7月25日 15時4分", at, "2006年1月2日 15時4分"},
+		{"2026年7月25日(土) 15:04", at, jst.LayoutJapaneseDateTime},
+		{"２０２６年７月２５日", day, "2006年1月2日"},
+		{"２０２６／０７／２５　１５：０４", at, "2006/1/2 15:04"},
+		{"  2026-07-25  ", day, "2006-1-2"},
+		{"2026-07-25 15:04 JST", at, jst.LayoutDisplay},
+		{"2026-07-25 06:04 UTC", at, jst.LayoutDisplay},
+		{"2026-07-25 06:04 GMT", at, jst.LayoutDisplay},
+		{"2026/07/25 15:04:00 JST", at, jst.LayoutTimestamp},
+		{"2026-07-25T06:04:00Z", at, time.RFC3339},
+		{"2026-07-25T15:04:00+09:00", at, time.RFC3339},
+		{"2026-07-25T15:04:00.5+09:00", at.Add(500 * time.Millisecond), time.RFC3339},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, layout, err := jst.ParseAny(tt.value)
+			if err != nil {
+				t.Fatalf("ParseAny() error = %v", err)
+			}
+			if !got.Equal(tt.want) || got.Location() != jst.Location() {
+				t.Errorf("ParseAny(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+			if layout != tt.wantLayout {
+				t.Errorf("ParseAny(%q) layout = %q, want %q", tt.value, layout, tt.wantLayout)
+			}
+		})
+	}
+}
+
+func TestParseAnyErrors(t *testing.T) {
+	for _, value := range []string{
+		"", "tomorrow", "2026-13-01", "2026年7月25日(日)", "25/07/2026",
+		// JST 以外の略称はオフセットが分からないため、誤った時刻にせず失敗させる。
+		"2026-07-25 15:04 PST", "2026-07-25 15:04 XYZ",
+	} {
+		if got, _, err := jst.ParseAny(value); !errors.Is(err, jst.ErrNoMatchingLayout) {
+			t.Errorf("ParseAny(%q) = %v, %v, want ErrNoMatchingLayout", value, got, err)
+		}
+	}
+}
+
+func TestParseAnyCustomLayouts(t *testing.T) {
+	layouts := append(jst.DefaultParseLayouts(), "02/01/2006")
+	got, layout, err := jst.ParseAny("25/07/2026", layouts...)
+	if err != nil || layout != "02/01/2006" || !got.Equal(time.Date(2026, time.July, 25, 0, 0, 0, 0, jst.Location())) {
+		t.Errorf("ParseAny() = %v, %q, %v", got, layout, err)
+	}
+	if _, _, err := jst.ParseAny("2026/7/25", "20060102"); err == nil {
+		t.Error("指定したレイアウト以外で解釈された")
+	}
+}
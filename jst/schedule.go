@@ -0,0 +1,339 @@
+package jst
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSchedule は、スケジュールの書式として解釈できないことを表します。
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+// maxScheduleDays は Next と Prev が探す日数の上限です。2 月 29 日だけの指定でも
+// 見つかるよう、閏年の間隔の最大 (8 年) より長くしています。
+const maxScheduleDays = 366 * 10
+
+// scheduleMacros は "@daily" などの短縮形です。
+var scheduleMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var (
+	monthNames   = []string{"", "JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+	weekdayNames = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+)
+
+// Schedule は cron 形式で指定した、JST で評価する実行予定です。ParseSchedule で作ります。
+//
+// 並行に使えます。
+type Schedule struct {
+	spec     string
+	calendar *Calendar
+
+	minute, hour, dom, month, dow uint64
+
+	// domStar と dowStar は、日と曜日のフィールドが "*" で始まるかです。cron と同じく、
+	// どちらかが "*" で始まれば両方に一致する日、そうでなければどちらかに一致する日に実行します。
+	domStar, dowStar bool
+
+	lastDay         bool  // 日のフィールドの "L"
+	nthBusinessDays []int // 日のフィールドの "1B" など
+	lastBusinessDay bool  // 日のフィールドの "LB"
+	businessDays    bool  // 曜日のフィールドの "B"
+	skipHolidays    bool  // 6 つ目のフィールドの "H"
+}
+
+// ParseSchedule は、土日と祝日を休業日とする暦で spec を解釈します。
+// 書式は Calendar.ParseSchedule を参照してください。
+func ParseSchedule(spec string) (*Schedule, error) {
+	return defaultCalendar.ParseSchedule(spec)
+}
+
+// ParseSchedule は、cron 形式の spec を JST で評価する Schedule に変換します。
+// 営業日の拡張は c の営業日で判定します。
+//
+// spec は "分 時 日 月 曜日" の 5 つのフィールドで、各フィールドには "*"、"5"、"1-5"、"*/15"、
+// "1-30/10" とそのカンマ区切りの並びを書けます。月と曜日は "JAN" や "MON" とも書け、
+// 曜日の 7 は日曜日です。"@daily" などの短縮形も使えます。加えて次の拡張があります。
+//
+//	日の "L"            月末日
+//	日の "1B"、"2B"…    その月の 1 番目、2 番目…の営業日
+//	日の "LB"           その月の最後の営業日
+//	曜日の "B"          営業日
+//	6 つ目の "H"        祝日（振替休日・国民の休日を含む）を除く
+//
+// 例えば "0 9 * * B" は毎営業日の 9 時、"0 10 1B * *" は毎月最初の営業日の 10 時、
+// "30 8 * * MON H" は祝日を除く毎週月曜日の 8 時 30 分です。
+func (c *Calendar) ParseSchedule(spec string) (*Schedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) == 1 && strings.HasPrefix(fields[0], "@") {
+		expanded, ok := scheduleMacros[strings.ToLower(fields[0])]
+		if !ok {
+			return nil, fmt.Errorf("parse schedule %q: %w: unknown macro", spec, ErrInvalidSchedule)
+		}
+		fields = strings.Fields(expanded)
+	}
+	if len(fields) == 6 {
+		if !strings.EqualFold(fields[5], "H") {
+			return nil, fmt.Errorf("parse schedule %q: %w: unknown modifier %q", spec, ErrInvalidSchedule, fields[5])
+		}
+	} else if len(fields) != 5 {
+		return nil, fmt.Errorf("parse schedule %q: %w: want 5 fields, got %d", spec, ErrInvalidSchedule, len(fields))
+	}
+
+	s := &Schedule{
+		spec:         spec,
+		calendar:     c,
+		domStar:      strings.HasPrefix(fields[2], "*"),
+		dowStar:      strings.HasPrefix(fields[4], "*"),
+		skipHolidays: len(fields) == 6,
+	}
+	var err error
+	if s.minute, err = parseScheduleField(fields[0], 0, 59, 59, nil, nil); err != nil {
+		return nil, fmt.Errorf("parse schedule %q: minute: %w", spec, err)
+	}
+	if s.hour, err = parseScheduleField(fields[1], 0, 23, 23, nil, nil); err != nil {
+		return nil, fmt.Errorf("parse schedule %q: hour: %w", spec, err)
+	}
+	if s.dom, err = parseScheduleField(fields[2], 1, 31, 31, nil, s.parseDOMExtension); err != nil {
+		return nil, fmt.Errorf("parse schedule %q: day of month: %w", spec, err)
+	}
+	if s.month, err = parseScheduleField(fields[3], 1, 12, 12, monthNames, nil); err != nil {
+		return nil, fmt.Errorf("parse schedule %q: month: %w", spec, err)
+	}
+	if s.dow, err = parseScheduleField(fields[4], 0, 7, 6, weekdayNames, s.parseDOWExtension); err != nil {
+		return nil, fmt.Errorf("parse schedule %q: day of week: %w", spec, err)
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1 << 0
+	}
+	return s, nil
+}
+
+// parseDOMExtension は日のフィールドの "L"、"nB"、"LB" を解釈し、拡張だったかを返します。
+func (s *Schedule) parseDOMExtension(item string) (bool, error) {
+	switch {
+	case item == "L":
+		s.lastDay = true
+	case item == "LB":
+		s.lastBusinessDay = true
+	case strings.HasSuffix(item, "B"):
+		n, err := strconv.Atoi(item[:len(item)-1])
+		if err != nil || n < 1 || n > 31 {
+			return true, fmt.Errorf("%w: %q", ErrInvalidSchedule, item)
+		}
+		s.nthBusinessDays = append(s.nthBusinessDays, n)
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// parseDOWExtension は曜日のフィールドの "B" を解釈し、拡張だったかを返します。
+func (s *Schedule) parseDOWExtension(item string) (bool, error) {
+	if item != "B" {
+		return false, nil
+	}
+	s.businessDays = true
+	return true, nil
+}
+
+// parseScheduleField は 1 つのフィールドを、low から high までの値のビット集合に変換します。
+// "*" と "5/10" のように終端を書かない項目は openHigh までとします。曜日の 7 は日曜日の別名のため、
+// 曜日では openHigh を 6 にして、"1/2" が日曜日を含まないようにします。
+// names は値の名前で、names[i] が値 i を表します。extension は拡張の項目を解釈します。
+func parseScheduleField(field string, low, high, openHigh int, names []string, extension func(string) (bool, error)) (uint64, error) {
+	var set uint64
+	for item := range strings.SplitSeq(strings.ToUpper(field), ",") {
+		if extension != nil {
+			ok, err := extension(item)
+			if err != nil {
+				return 0, err
+			}
+			if ok {
+				continue
+			}
+		}
+
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("%w: bad step in %q", ErrInvalidSchedule, item)
+			}
+			step = n
+		}
+
+		lo, hi := low, openHigh
+		if rangePart != "*" {
+			loPart, hiPart, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = parseScheduleValue(loPart, low, high, names); err != nil {
+				return 0, err
+			}
+			switch {
+			case isRange:
+				if hi, err = parseScheduleValue(hiPart, low, high, names); err != nil {
+					return 0, err
+				}
+				if hi < lo {
+					return 0, fmt.Errorf("%w: reversed range %q", ErrInvalidSchedule, item)
+				}
+			case !hasStep:
+				// "5/10" は 5 から最大値までの 10 刻み、"5" は 5 だけです。
+				hi = lo
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
+
+// parseScheduleValue は数値か名前を low から high までの値に変換します。
+func parseScheduleValue(value string, low, high int, names []string) (int, error) {
+	for i, name := range names {
+		if name != "" && value == name {
+			return i, nil
+		}
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < low || n > high {
+		return 0, fmt.Errorf("%w: %q is not in %d-%d", ErrInvalidSchedule, value, low, high)
+	}
+	return n, nil
+}
+
+// String は ParseSchedule に渡した書式を返します。
+func (s *Schedule) String() string {
+	return s.spec
+}
+
+// Next は、after より後で最初に実行する時刻を UTC で返します。実行は分単位で、秒以下は 0 です。
+// 10 年先まで実行する日が無ければゼロ値を返します。
+func (s *Schedule) Next(after time.Time) time.Time {
+	t := From(after).Truncate(time.Minute).Add(time.Minute)
+	day, from := StartOfDay(t), t.Hour()*60+t.Minute()
+	for range maxScheduleDays {
+		if s.matchDay(day) {
+			for m := from; m < 24*60; m++ {
+				if s.matchMinute(m) {
+					return atMinute(day, m).UTC()
+				}
+			}
+		}
+		day, from = day.AddDate(0, 0, 1), 0
+	}
+	return time.Time{}
+}
+
+// Prev は、before より前で最後に実行した時刻を UTC で返します。
+// 10 年前まで実行した日が無ければゼロ値を返します。
+func (s *Schedule) Prev(before time.Time) time.Time {
+	b := From(before)
+	t := b.Truncate(time.Minute)
+	if t.Equal(b) {
+		t = t.Add(-time.Minute)
+	}
+	day, to := StartOfDay(t), t.Hour()*60+t.Minute()
+	for range maxScheduleDays {
+		if s.matchDay(day) {
+			for m := to; m >= 0; m-- {
+				if s.matchMinute(m) {
+					return atMinute(day, m).UTC()
+				}
+			}
+		}
+		day, to = day.AddDate(0, 0, -1), 24*60-1
+	}
+	return time.Time{}
+}
+
+// atMinute は、JST の 0 時で表した day の、0 時から m 分後の時刻を返します。
+func atMinute(day time.Time, m int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), m/60, m%60, 0, 0, Location())
+}
+
+// matchMinute は、0 時から m 分後の時刻が分と時のフィールドに一致するかを返します。
+func (s *Schedule) matchMinute(m int) bool {
+	return s.hour&(1<<(m/60)) != 0 && s.minute&(1<<(m%60)) != 0
+}
+
+// matchDay は、JST の 0 時で表した day が実行する日かを返します。
+func (s *Schedule) matchDay(day time.Time) bool {
+	if s.month&(1<<day.Month()) == 0 {
+		return false
+	}
+	if s.skipHolidays && IsHoliday(day) {
+		return false
+	}
+	dom, dow := s.matchDOM(day), s.matchDOW(day)
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// matchDOM は day が日のフィールドに一致するかを返します。
+func (s *Schedule) matchDOM(day time.Time) bool {
+	if s.dom&(1<<day.Day()) != 0 {
+		return true
+	}
+	if s.lastDay && day.AddDate(0, 0, 1).Day() == 1 {
+		return true
+	}
+	if len(s.nthBusinessDays) == 0 && !s.lastBusinessDay {
+		return false
+	}
+	if !s.calendar.isOpen(day) {
+		return false
+	}
+	if s.lastBusinessDay && s.isLastBusinessDay(day) {
+		return true
+	}
+	for _, n := range s.nthBusinessDays {
+		if s.businessDayOfMonth(day) == n {
+			return true
+		}
+	}
+	return false
+}
+
+// matchDOW は day が曜日のフィールドに一致するかを返します。
+func (s *Schedule) matchDOW(day time.Time) bool {
+	if s.dow&(1<<day.Weekday()) != 0 {
+		return true
+	}
+	return s.businessDays && s.calendar.isOpen(day)
+}
+
+// businessDayOfMonth は、営業日の day がその月の何番目の営業日かを返します。
+func (s *Schedule) businessDayOfMonth(day time.Time) int {
+	n := 0
+	for d := StartOfMonth(day); !d.After(day); d = d.AddDate(0, 0, 1) {
+		if s.calendar.isOpen(d) {
+			n++
+		}
+	}
+	return n
+}
+
+// isLastBusinessDay は、営業日の day の後にその月の営業日が無いかを返します。
+func (s *Schedule) isLastBusinessDay(day time.Time) bool {
+	for d := day.AddDate(0, 0, 1); d.Month() == day.Month(); d = d.AddDate(0, 0, 1) {
+		if s.calendar.isOpen(d) {
+			return false
+		}
+	}
+	return true
+}
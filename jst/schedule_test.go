@@ -0,0 +1,269 @@
+package jst_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shouni/go-utils/jst"
+)
+
+// minute は JST の日時を返します。
+func minute(year int, month time.Month, day, hour, m int) time.Time {
+	return time.Date(year, month, day, hour, m, 0, 0, jst.Location())
+}
+
+func TestScheduleNext(t *testing.T) {
+	tests := []struct {
+		name  string
+		spec  string
+		after time.Time
+		want  []time.Time
+	}{
+		{
+			name:  "毎時 0 分と 30 分",
+			spec:  "0,30 * * * *",
+			after: minute(2026, time.July, 25, 23, 15),
+			want:  []time.Time{minute(2026, time.July, 25, 23, 30), minute(2026, time.July, 26, 0, 0), minute(2026, time.July, 26, 0, 30)},
+		},
+		{
+			name:  "after と同じ時刻は含まない",
+			spec:  "0 9 * * *",
+			after: minute(2026, time.July, 25, 9, 0),
+			want:  []time.Time{minute(2026, time.July, 26, 9, 0)},
+		},
+		{
+			name:  "月をまたぐ",
+			spec:  "0 0 31 * *",
+			after: minute(2026, time.April, 1, 0, 0),
+			want:  []time.Time{minute(2026, time.May, 31, 0, 0), minute(2026, time.July, 31, 0, 0)},
+		},
+		{
+			name:  "年をまたぐ",
+			spec:  "@yearly",
+			after: minute(2026, time.December, 31, 23, 59),
+			want:  []time.Time{minute(2027, time.January, 1, 0, 0), minute(2028, time.January, 1, 0, 0)},
+		},
+		{
+			name:  "閏日",
+			spec:  "0 12 29 FEB *",
+			after: minute(2026, time.March, 1, 0, 0),
+			want:  []time.Time{minute(2028, time.February, 29, 12, 0), minute(2032, time.February, 29, 12, 0)},
+		},
+		{
+			name:  "ステップと範囲",
+			spec:  "*/20 8-9 * * *",
+			after: minute(2026, time.July, 25, 9, 40),
+			want:  []time.Time{minute(2026, time.July, 26, 8, 0), minute(2026, time.July, 26, 8, 20)},
+		},
+		{
+			name:  "日と曜日の両方を指定するといずれかに一致する日",
+			spec:  "0 0 1 * MON",
+			after: minute(2026, time.June, 28, 0, 0),
+			want:  []time.Time{minute(2026, time.June, 29, 0, 0), minute(2026, time.July, 1, 0, 0), minute(2026, time.July, 6, 0, 0)},
+		},
+		{
+			name:  "曜日の 7 は日曜日",
+			spec:  "0 0 * * 7",
+			after: minute(2026, time.July, 25, 0, 0),
+			want:  []time.Time{minute(2026, time.July, 26, 0, 0)},
+		},
+		{
+			// 曜日の 7 は日曜日の別名のため、終端を書かないステップは土曜日までとする。
+			name:  "曜日のステップは日曜日を含まない",
+			spec:  "0 9 * * 1/2",
+			after: minute(2026, time.July, 25, 9, 0),
+			want:  []time.Time{minute(2026, time.July, 27, 9, 0), minute(2026, time.July, 29, 9, 0), minute(2026, time.July, 31, 9, 0), minute(2026, time.August, 3, 9, 0)},
+		},
+		{
+			name:  "曜日の */2 は日火木土",
+			spec:  "0 9 * * */2",
+			after: minute(2026, time.July, 25, 9, 0),
+			want:  []time.Time{minute(2026, time.July, 26, 9, 0), minute(2026, time.July, 28, 9, 0), minute(2026, time.July, 30, 9, 0), minute(2026, time.August, 1, 9, 0)},
+		},
+		{
+			name:  "範囲で書いた 7 は日曜日",
+			spec:  "0 9 * * 5-7",
+			after: minute(2026, time.July, 24, 9, 0),
+			want:  []time.Time{minute(2026, time.July, 25, 9, 0), minute(2026, time.July, 26, 9, 0), minute(2026, time.July, 31, 9, 0)},
+		},
+		{
+			name:  "月末日",
+			spec:  "0 18 L * *",
+			after: minute(2026, time.January, 31, 18, 0),
+			want:  []time.Time{minute(2026, time.February, 28, 18, 0), minute(2026, time.March, 31, 18, 0)},
+		},
+		{
+			// 2026-07-20 は海の日。
+			name:  "毎営業日は土日と祝日を除く",
+			spec:  "0 9 * * B",
+			after: minute(2026, time.July, 17, 9, 0),
+			want:  []time.Time{minute(2026, time.July, 21, 9, 0), minute(2026, time.July, 22, 9, 0)},
+		},
+		{
+			name:  "H は祝日を除く",
+			spec:  "30 8 * * MON H",
+			after: minute(2026, time.July, 13, 9, 0),
+			want:  []time.Time{minute(2026, time.July, 27, 8, 30)},
+		},
+		{
+			// 2027-01-01 は元日、01-02 と 01-03 は土日、01-11 は成人の日。
+			name:  "毎月最初の営業日は年をまたいで祝日を飛ばす",
+			spec:  "0 10 1B * *",
+			after: minute(2026, time.December, 1, 10, 0),
+			want:  []time.Time{minute(2027, time.January, 4, 10, 0), minute(2027, time.February, 1, 10, 0)},
+		},
+		{
+			name:  "毎月 2 番目の営業日",
+			spec:  "0 10 2B * *",
+			after: minute(2026, time.December, 31, 0, 0),
+			want:  []time.Time{minute(2027, time.January, 5, 10, 0)},
+		},
+		{
+			// 2026-05-31 は日曜日、2026-10-31 は土曜日。
+			name:  "毎月最後の営業日",
+			spec:  "0 17 LB * *",
+			after: minute(2026, time.May, 1, 0, 0),
+			want:  []time.Time{minute(2026, time.May, 29, 17, 0), minute(2026, time.June, 30, 17, 0)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := jst.ParseSchedule(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseSchedule(%q) error = %v", tt.spec, err)
+			}
+			got := tt.after
+			for _, want := range tt.want {
+				got = s.Next(got)
+				if !got.Equal(want) || got.Location() != time.UTC {
+					t.Fatalf("Next() = %v, want %v in UTC", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSchedulePrev(t *testing.T) {
+	tests := []struct {
+		name   string
+		spec   string
+		before time.Time
+		want   []time.Time
+	}{
+		{
+			name:   "before と同じ時刻は含まない",
+			spec:   "0 9 * * *",
+			before: minute(2026, time.July, 25, 9, 0),
+			want:   []time.Time{minute(2026, time.July, 24, 9, 0)},
+		},
+		{
+			name:   "秒があれば同じ分を含む",
+			spec:   "0 9 * * *",
+			before: minute(2026, time.July, 25, 9, 0).Add(time.Second),
+			want:   []time.Time{minute(2026, time.July, 25, 9, 0)},
+		},
+		{
+			name:   "年をまたいで遡る",
+			spec:   "0 0 1 * *",
+			before: minute(2027, time.January, 1, 0, 0),
+			want:   []time.Time{minute(2026, time.December, 1, 0, 0), minute(2026, time.November, 1, 0, 0)},
+		},
+		{
+			name:   "月末日を遡る",
+			spec:   "59 23 L * *",
+			before: minute(2028, time.March, 31, 23, 59),
+			want:   []time.Time{minute(2028, time.February, 29, 23, 59), minute(2028, time.January, 31, 23, 59)},
+		},
+		{
+			// 既定の暦は年末年始を休業日としないため、2026-12-31 (木) が 12 月の最後の営業日。
+			name:   "毎月最後の営業日を年をまたいで遡る",
+			spec:   "0 17 LB * *",
+			before: minute(2027, time.January, 29, 17, 0),
+			want:   []time.Time{minute(2026, time.December, 31, 17, 0), minute(2026, time.November, 30, 17, 0)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := jst.ParseSchedule(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseSchedule(%q) error = %v", tt.spec, err)
+			}
+			got := tt.before
+			for _, want := range tt.want {
+				got = s.Prev(got)
+				if !got.Equal(want) || got.Location() != time.UTC {
+					t.Fatalf("Prev() = %v, want %v in UTC", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCalendarParseSchedule(t *testing.T) {
+	// 年末年始を休業日とする暦では、1 月の最初の営業日は 1 月 4 日以降になる。
+	c := &jst.Calendar{Closures: []jst.Closure{jst.YearEndClosure}}
+	s, err := c.ParseSchedule("0 10 1B * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	after := minute(2025, time.December, 1, 10, 0)
+	if got, want := s.Next(after), minute(2026, time.January, 5, 10, 0); !got.Equal(want) {
+		t.Errorf("Next() = %v, want %v", got, want)
+	}
+
+	s, err = c.ParseSchedule("0 17 LB * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := s.Next(after), minute(2025, time.December, 26, 17, 0); !got.Equal(want) {
+		t.Errorf("Next() = %v, want %v", got, want)
+	}
+}
+
+func TestScheduleNoMatch(t *testing.T) {
+	s, err := jst.ParseSchedule("0 0 30 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := minute(2026, time.July, 25, 0, 0)
+	if got := s.Next(now); !got.IsZero() {
+		t.Errorf("Next() = %v, want zero", got)
+	}
+	if got := s.Prev(now); !got.IsZero() {
+		t.Errorf("Prev() = %v, want zero", got)
+	}
+}
+
+func TestParseScheduleErrors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"* * * FOO *",
+		"* * 0B * *",
+		"* * * * * X",
+		"@every",
+	} {
+		if _, err := jst.ParseSchedule(spec); !errors.Is(err, jst.ErrInvalidSchedule) {
+			t.Errorf("ParseSchedule(%q) error = %v, want ErrInvalidSchedule", spec, err)
+		}
+	}
+}
+
+func TestScheduleString(t *testing.T) {
+	s, err := jst.ParseSchedule("0 9 * * B")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.String(); got != "0 9 * * B" {
+		t.Errorf("String() = %q", got)
+	}
+}
@@ -0,0 +1,280 @@
+package slogctx
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrAsyncClosed は、Close 後の AsyncHandler へレコードが渡されたことを表します。
+var ErrAsyncClosed = errors.New("async handler is closed")
+
+// DefaultAsyncQueueSize は AsyncOptions.QueueSize を省略したときのキューの長さです。
+const DefaultAsyncQueueSize = 1024
+
+// OverflowPolicy は、AsyncHandler のキューが満杯のときの振る舞いです。
+type OverflowPolicy int
+
+const (
+	// OverflowBlock は、キューに空きができるまで呼び出し元を待たせます。ログを失いません。
+	OverflowBlock OverflowPolicy = iota
+
+	// OverflowDropNewest は、新しいレコードを捨てます。呼び出し元を待たせません。
+	OverflowDropNewest
+
+	// OverflowDropLowest は、キュー内で最もレベルの低いレコードを捨てて新しいレコードを入れます。
+	// 新しいレコードの方が低い（または同じ）レベルなら、新しいレコードを捨てます。
+	// 負荷が高いときにデバッグログより先にエラーログを失わないようにするためのものです。
+	OverflowDropLowest
+)
+
+// AsyncOptions は NewAsyncHandler の設定です。ゼロ値のままでも使えます。
+type AsyncOptions struct {
+	// QueueSize はキューに溜められるレコードの数です。0 以下なら DefaultAsyncQueueSize です。
+	QueueSize int
+
+	// Overflow はキューが満杯のときの振る舞いです。既定は OverflowBlock です。
+	Overflow OverflowPolicy
+
+	// OnError は、委譲先の Handle が返したエラーを受け取ります。nil なら数えるだけです。
+	// バックグラウンドの goroutine から呼ばれます。
+	OnError func(error)
+}
+
+// AsyncStats は AsyncHandler の累計の件数です。
+type AsyncStats struct {
+	// Enqueued はキューへ入ったレコードの数です。
+	Enqueued uint64
+	// Written は委譲先が処理を終えたレコードの数です（エラーを返したものを含みます）。
+	Written uint64
+	// Dropped は、キューの溢れや Close 後の呼び出しで捨てたレコードの数です。
+	Dropped uint64
+	// Failed は委譲先の Handle がエラーを返した数です。
+	Failed uint64
+}
+
+// AsyncHandler は、レコードをキューへ積んでバックグラウンドの goroutine から委譲先へ渡す slog.Handler です。
+//
+// 呼び出し元は JSON のエンコードや書き込みを待たずに戻れます。その代わり、プロセスの終了前に
+// Close（または Flush）を呼ばないと、キューに残ったログを失います。
+//
+// レコードは積む時点で複製し、context 属性の付与と LogValuer の解決も済ませます。
+// レコードの属性の格納領域は呼び出し元と共有されており、値も後から変わりうるためです。
+// 委譲先へ渡す context はキャンセルを外したものです。WithAttrs / WithGroup で派生したハンドラーは
+// キューと goroutine を共有します。
+type AsyncHandler struct {
+	base  slog.Handler
+	queue *asyncQueue
+}
+
+// asyncItem はキューに積まれたレコード 1 件です。
+type asyncItem struct {
+	ctx     context.Context
+	handler slog.Handler
+	record  slog.Record
+}
+
+// asyncQueue は派生したハンドラー間で共有されるキューです。
+type asyncQueue struct {
+	size     int
+	overflow OverflowPolicy
+	onError  func(error)
+
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	items    []asyncItem
+	spare    []asyncItem
+	closed   bool
+
+	// queued / settled は、キューへ入ったレコードと、書き込みか破棄で処理が済んだレコードの通し番号です。
+	// Flush はこれを使い、呼び出し時点までに積まれたレコードの処理を待ちます。
+	queued   uint64
+	settled  uint64
+	progress chan struct{}
+	done     chan struct{}
+
+	enqueued, written, dropped, failed atomic.Uint64
+}
+
+// NewAsyncHandler は base を AsyncHandler で包み、バックグラウンドの goroutine を開始します。
+func NewAsyncHandler(base slog.Handler, opts AsyncOptions) *AsyncHandler {
+	size := opts.QueueSize
+	if size <= 0 {
+		size = DefaultAsyncQueueSize
+	}
+	q := &asyncQueue{
+		size:     size,
+		overflow: opts.Overflow,
+		onError:  opts.OnError,
+		items:    make([]asyncItem, 0, size),
+		spare:    make([]asyncItem, 0, size),
+		progress: make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	q.notEmpty = sync.NewCond(&q.mu)
+	q.notFull = sync.NewCond(&q.mu)
+	go q.run()
+	return &AsyncHandler{base: base, queue: q}
+}
+
+func (h *AsyncHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.base.Enabled(ctx, level)
+}
+
+// Handle はレコードを複製してキューへ積みます。Close 後は ErrAsyncClosed を返します。
+func (h *AsyncHandler) Handle(ctx context.Context, record slog.Record) error {
+	attrs := recordAttrs(record)
+	attrs = resolveAttrs(append(attrs, attrsFrom(ctx)...))
+	item := asyncItem{
+		ctx:     withoutAttrs(context.WithoutCancel(ctx)),
+		handler: h.base,
+		record:  rebuildRecord(record, attrs),
+	}
+	return h.queue.push(item)
+}
+
+func (h *AsyncHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &AsyncHandler{base: h.base.WithAttrs(resolveAttrs(attrs)), queue: h.queue}
+}
+
+func (h *AsyncHandler) WithGroup(name string) slog.Handler {
+	return &AsyncHandler{base: h.base.WithGroup(name), queue: h.queue}
+}
+
+// Flush は、呼び出し時点までに積まれたレコードの処理が済むまで待ちます。
+// ctx が先に終われば ctx.Err() を返します。
+func (h *AsyncHandler) Flush(ctx context.Context) error {
+	q := h.queue
+	q.mu.Lock()
+	target := q.queued
+	for q.settled < target {
+		progress := q.progress
+		q.mu.Unlock()
+		select {
+		case <-progress:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		q.mu.Lock()
+	}
+	q.mu.Unlock()
+	return nil
+}
+
+// Close は新しいレコードの受け付けを止め、キューに残ったレコードを書き終えるまで待ちます。
+// ctx が先に終われば ctx.Err() を返します（書き込みはバックグラウンドで続きます）。
+// 何度呼んでも構いません。
+func (h *AsyncHandler) Close(ctx context.Context) error {
+	q := h.queue
+	q.mu.Lock()
+	q.closed = true
+	q.notEmpty.Broadcast()
+	q.notFull.Broadcast()
+	q.mu.Unlock()
+
+	select {
+	case <-q.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stats は累計の件数を返します。
+func (h *AsyncHandler) Stats() AsyncStats {
+	q := h.queue
+	return AsyncStats{
+		Enqueued: q.enqueued.Load(),
+		Written:  q.written.Load(),
+		Dropped:  q.dropped.Load(),
+		Failed:   q.failed.Load(),
+	}
+}
+
+// push は溢れたときの方針に従ってレコードをキューへ積みます。
+func (q *asyncQueue) push(item asyncItem) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for !q.closed && len(q.items) >= q.size {
+		switch q.overflow {
+		case OverflowDropNewest:
+			q.dropped.Add(1)
+			return nil
+		case OverflowDropLowest:
+			lowest := 0
+			for i, queued := range q.items {
+				if queued.record.Level < q.items[lowest].record.Level {
+					lowest = i
+				}
+			}
+			if q.items[lowest].record.Level >= item.record.Level {
+				q.dropped.Add(1)
+				return nil
+			}
+			q.items = slices.Delete(q.items, lowest, lowest+1)
+			q.dropped.Add(1)
+			q.settle(1)
+		default:
+			q.notFull.Wait()
+		}
+	}
+	if q.closed {
+		q.dropped.Add(1)
+		return ErrAsyncClosed
+	}
+
+	q.items = append(q.items, item)
+	q.queued++
+	q.enqueued.Add(1)
+	q.notEmpty.Signal()
+	return nil
+}
+
+// settle は n 件の処理が済んだことを記録し、Flush の待ち手を起こします。q.mu を保持して呼びます。
+func (q *asyncQueue) settle(n int) {
+	q.settled += uint64(n)
+	close(q.progress)
+	q.progress = make(chan struct{})
+}
+
+// run はキューからレコードをまとめて取り出し、委譲先へ渡し続けます。
+func (q *asyncQueue) run() {
+	defer close(q.done)
+
+	for {
+		q.mu.Lock()
+		for len(q.items) == 0 && !q.closed {
+			q.notEmpty.Wait()
+		}
+		if len(q.items) == 0 {
+			q.mu.Unlock()
+			return
+		}
+		batch := q.items
+		q.items = q.spare[:0]
+		q.notFull.Broadcast()
+		q.mu.Unlock()
+
+		for _, item := range batch {
+			if err := item.handler.Handle(item.ctx, item.record); err != nil {
+				q.failed.Add(1)
+				if q.onError != nil {
+					q.onError(err)
+				}
+			}
+			q.written.Add(1)
+		}
+
+		q.mu.Lock()
+		// 取り出したレコードへの参照を残さないよう消してから使い回します。
+		clear(batch)
+		q.spare = batch[:0]
+		q.settle(len(batch))
+		q.mu.Unlock()
+	}
+}
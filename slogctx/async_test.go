@@ -0,0 +1,204 @@
+package slogctx
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gateHandler は、started を通知したあと release が閉じられるまで Handle を止めるハンドラーです。
+// AsyncHandler のバックグラウンド処理を止めてキューを満たすために使います。
+type gateHandler struct {
+	slog.Handler
+	once    *sync.Once
+	started chan struct{}
+	release chan struct{}
+}
+
+func newGateHandler(base slog.Handler) gateHandler {
+	return gateHandler{Handler: base, once: &sync.Once{}, started: make(chan struct{}), release: make(chan struct{})}
+}
+
+func (g gateHandler) Handle(ctx context.Context, r slog.Record) error {
+	g.once.Do(func() { close(g.started) })
+	<-g.release
+	return g.Handler.Handle(ctx, r)
+}
+
+func messages(rec *Recorder) []string {
+	var msgs []string
+	for _, e := range rec.Entries() {
+		msgs = append(msgs, e.Message())
+	}
+	return msgs
+}
+
+func TestAsyncHandlerWritesInOrder(t *testing.T) {
+	rec := NewRecorder()
+	h := NewAsyncHandler(rec, AsyncOptions{})
+	logger := slog.New(h).With("component", "worker")
+
+	ctx := With(context.Background(), slog.String("job_id", "job-1"))
+	for _, msg := range []string{"a", "b", "c"} {
+		logger.InfoContext(ctx, msg)
+	}
+	if err := h.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if got := messages(rec); len(got) != 3 || got[0] != "a" || got[2] != "c" {
+		t.Errorf("messages = %v, want [a b c]", got)
+	}
+	rec.RequireLogged(t, slog.LevelInfo, "b", slog.String("component", "worker"), slog.String("job_id", "job-1"))
+
+	if err := h.Handle(context.Background(), slog.NewRecord(time.Time{}, slog.LevelInfo, "late", 0)); !errors.Is(err, ErrAsyncClosed) {
+		t.Errorf("Close 後の Handle() error = %v, want ErrAsyncClosed", err)
+	}
+	if got := h.Stats(); got.Enqueued != 3 || got.Written != 3 || got.Dropped != 1 {
+		t.Errorf("Stats() = %+v, want Enqueued=3 Written=3 Dropped=1", got)
+	}
+}
+
+// 呼び出し元があとから属性の値を変えても、積んだ時点の値で出力されること。
+func TestAsyncHandlerResolvesBeforeEnqueue(t *testing.T) {
+	rec := NewRecorder()
+	gate := newGateHandler(rec)
+	h := NewAsyncHandler(gate, AsyncOptions{})
+	logger := slog.New(h)
+
+	logger.Info("first")
+	<-gate.started
+
+	state := &mutableValuer{value: "before"}
+	ctx, cancel := context.WithCancel(With(context.Background(), slog.String("job_id", "job-1")))
+	logger.InfoContext(ctx, "second", slog.Any("state", state))
+	state.value = "after"
+	cancel()
+
+	close(gate.release)
+	if err := h.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	rec.RequireLogged(t, slog.LevelInfo, "second", slog.String("state", "before"), slog.String("job_id", "job-1"))
+}
+
+type mutableValuer struct{ value string }
+
+func (v *mutableValuer) LogValue() slog.Value { return slog.StringValue(v.value) }
+
+func TestAsyncHandlerOverflow(t *testing.T) {
+	tests := []struct {
+		name     string
+		overflow OverflowPolicy
+		want     []string
+	}{
+		{"新しいものを捨てる", OverflowDropNewest, []string{"blocker", "debug", "info"}},
+		{"最も低いレベルを捨てる", OverflowDropLowest, []string{"blocker", "info", "error"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := NewRecorder()
+			gate := newGateHandler(rec)
+			h := NewAsyncHandler(gate, AsyncOptions{QueueSize: 2, Overflow: tt.overflow})
+			logger := slog.New(h)
+
+			logger.Info("blocker")
+			<-gate.started // blocker が取り出され、キューが空になるのを待つ
+
+			logger.Debug("debug")
+			logger.Info("info")
+			logger.Error("error") // キューが満杯
+
+			close(gate.release)
+			if err := h.Close(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			got := messages(rec)
+			if len(got) != len(tt.want) {
+				t.Fatalf("messages = %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Fatalf("messages = %v, want %v", got, tt.want)
+				}
+			}
+			if dropped := h.Stats().Dropped; dropped != 1 {
+				t.Errorf("Dropped = %d, want 1", dropped)
+			}
+		})
+	}
+}
+
+func TestAsyncHandlerBlockAndFlush(t *testing.T) {
+	rec := NewRecorder()
+	gate := newGateHandler(rec)
+	h := NewAsyncHandler(gate, AsyncOptions{QueueSize: 1})
+	logger := slog.New(h)
+
+	logger.Info("blocker")
+	<-gate.started
+	logger.Info("queued")
+
+	blocked := make(chan struct{})
+	go func() {
+		logger.Info("waits for space")
+		close(blocked)
+	}()
+
+	// 書き込みが止まっている間は Flush がタイムアウトすること。
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := h.Flush(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Flush() error = %v, want context.DeadlineExceeded", err)
+	}
+	select {
+	case <-blocked:
+		t.Fatal("キューが満杯なのに呼び出し元が待たされなかった")
+	default:
+	}
+
+	close(gate.release)
+	<-blocked
+	if err := h.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if got := messages(rec); len(got) != 3 {
+		t.Errorf("messages = %v, want 3 件", got)
+	}
+	if err := h.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := h.Stats(); got.Dropped != 0 || got.Written != 3 {
+		t.Errorf("Stats() = %+v, want Written=3 Dropped=0", got)
+	}
+}
+
+func TestAsyncHandlerOnError(t *testing.T) {
+	errSink := errors.New("sink")
+	var mu sync.Mutex
+	var got []error
+	h := NewAsyncHandler(errHandler{Handler: NewRecorder(), err: errSink}, AsyncOptions{
+		OnError: func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			got = append(got, err)
+		},
+	})
+	slog.New(h).Info("msg")
+	if err := h.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != 1 || !errors.Is(got[0], errSink) {
+		t.Errorf("OnError に渡されたエラー = %v, want [sink]", got)
+	}
+	if failed := h.Stats().Failed; failed != 1 {
+		t.Errorf("Failed = %d, want 1", failed)
+	}
+}
@@ -0,0 +1,260 @@
+package slogctx
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// Cloud Logging の severity に対応させるための追加のレベルです。
+// slog の標準レベルの間に置くことで、既存のレベルとの大小関係を保ちます。
+const (
+	LevelNotice    = slog.Level(2)
+	LevelCritical  = slog.Level(12)
+	LevelAlert     = slog.Level(16)
+	LevelEmergency = slog.Level(20)
+)
+
+// Cloud Logging が特別扱いする、トレース以外の構造化ログのフィールド名です。
+const (
+	CloudLabelsKey         = "logging.googleapis.com/labels"
+	CloudSourceLocationKey = "logging.googleapis.com/sourceLocation"
+)
+
+// CloudLoggingOptions は NewCloudLoggingHandler の設定です。nil でも使えます。
+type CloudLoggingOptions struct {
+	// Level は出力する最小レベルです。nil なら Info です。
+	Level slog.Leveler
+
+	// AddSource が true なら、呼び出し元を logging.googleapis.com/sourceLocation として出力します。
+	AddSource bool
+
+	// ProjectID が空でなければ、projects/ で始まらないトレース ID を
+	// projects/{ProjectID}/traces/{TraceID} の形式へ補います。
+	ProjectID string
+
+	// ReplaceAttr は slog.HandlerOptions.ReplaceAttr と同じく属性を書き換えます。
+	// severity / message / sourceLocation への詰め替えが済んだあとに呼ばれます。
+	ReplaceAttr func(groups []string, a slog.Attr) slog.Attr
+}
+
+// NewCloudLoggingHandler は、Cloud Logging の構造化ログの形式で w へ JSON を書くハンドラーを返します。
+//
+// level は severity へ、msg は message へ詰め替え、LevelNotice などの追加のレベルも
+// 対応する severity にします。With で context に積んだトレース・スパン・ラベル
+// （CloudTraceKey、CloudSpanIDKey、CloudTraceSampledKey、CloudLabelsKey）は、
+// logger.WithGroup の中で記録されてもトップレベルへ引き上げます。Cloud Logging は
+// トップレベルにあるときしか特別なフィールドとして扱わないためです。ラベルは
+// slog.Group で積み、複数の箇所で積んだものは 1 つにまとめます（後から積んだ値が優先）。
+//
+// context 属性は自身で付与するため NewHandler で包む必要はありません（包んでも二重には付きません）。
+func NewCloudLoggingHandler(w io.Writer, opts *CloudLoggingOptions) slog.Handler {
+	if opts == nil {
+		opts = &CloudLoggingOptions{}
+	}
+	replace := opts.ReplaceAttr
+	root := slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:     opts.Level,
+		AddSource: opts.AddSource,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 {
+				a = cloudLoggingAttr(a)
+			}
+			if replace != nil {
+				return replace(groups, a)
+			}
+			return a
+		},
+	})
+	return &cloudHandler{root: root, projectID: opts.ProjectID}
+}
+
+// cloudLoggingAttr は組み込みの属性を Cloud Logging のフィールドへ詰め替えます。
+func cloudLoggingAttr(a slog.Attr) slog.Attr {
+	switch a.Key {
+	case slog.LevelKey:
+		if level, ok := a.Value.Any().(slog.Level); ok {
+			return slog.String("severity", Severity(level))
+		}
+	case slog.MessageKey:
+		a.Key = "message"
+	case slog.SourceKey:
+		if src, ok := a.Value.Any().(*slog.Source); ok {
+			return slog.Group(CloudSourceLocationKey,
+				slog.String("file", src.File),
+				// LogEntrySourceLocation の line は int64 で、JSON では文字列で表されます。
+				slog.String("line", strconv.Itoa(src.Line)),
+				slog.String("function", src.Function),
+			)
+		}
+	}
+	return a
+}
+
+// Severity は slog のレベルを Cloud Logging の severity へ対応させます。
+// 各 severity は次の severity の直前までの範囲を受け持ちます（INFO+1 は INFO、WARN+2 は WARNING）。
+func Severity(level slog.Level) string {
+	switch {
+	case level < slog.LevelInfo:
+		return "DEBUG"
+	case level < LevelNotice:
+		return "INFO"
+	case level < slog.LevelWarn:
+		return "NOTICE"
+	case level < slog.LevelError:
+		return "WARNING"
+	case level < LevelCritical:
+		return "ERROR"
+	case level < LevelAlert:
+		return "CRITICAL"
+	case level < LevelEmergency:
+		return "ALERT"
+	default:
+		return "EMERGENCY"
+	}
+}
+
+// cloudHandler は特別なフィールドをトップレベルへ引き上げてから JSON へ書く slog.Handler です。
+//
+// 引き上げのためにグループを自前で管理し、出力時に入れ子の属性を組み立てます。委譲先の
+// WithGroup を使うと、グループを開いたあとの属性をトップレベルへ戻せないためです。
+type cloudHandler struct {
+	root      slog.Handler
+	projectID string
+	goas      []groupOrAttrs
+}
+
+func (h *cloudHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.root.Enabled(ctx, level)
+}
+
+func (h *cloudHandler) Handle(ctx context.Context, record slog.Record) error {
+	var hoisted cloudFields
+
+	attrs := recordAttrs(record)
+	attrs = hoisted.take(append(attrs, attrsFrom(ctx)...))
+
+	for i := len(h.goas) - 1; i >= 0; i-- {
+		goa := h.goas[i]
+		if goa.group == "" {
+			attrs = append(hoisted.take(goa.attrs), attrs...)
+			continue
+		}
+		if len(attrs) == 0 {
+			continue
+		}
+		attrs = []slog.Attr{{Key: goa.group, Value: slog.GroupValue(attrs...)}}
+	}
+
+	out := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	out.AddAttrs(hoisted.attrs(h.projectID)...)
+	out.AddAttrs(attrs...)
+	return h.root.Handle(withoutAttrs(ctx), out)
+}
+
+func (h *cloudHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.with(groupOrAttrs{attrs: attrs})
+}
+
+func (h *cloudHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.with(groupOrAttrs{group: name})
+}
+
+func (h *cloudHandler) with(goa groupOrAttrs) *cloudHandler {
+	goas := make([]groupOrAttrs, 0, len(h.goas)+1)
+	goas = append(goas, h.goas...)
+	goas = append(goas, goa)
+	return &cloudHandler{root: h.root, projectID: h.projectID, goas: goas}
+}
+
+// cloudFields はトップレベルへ引き上げる特別なフィールドです。
+type cloudFields struct {
+	trace, span, sampled *slog.Attr
+	labels               []slog.Attr
+}
+
+// take は attrs から特別なフィールドを取り出し、残りを返します。
+//
+// 内側（呼び出し時の属性）から外側（logger.With）の順に呼ばれ、各 attrs は後ろから走査するため、
+// 先に取り出したもの（より内側、より後に積まれたもの）を優先して上書きしません。
+// ラベルも同じ順で集め、attrs でまとめるときに並びを戻します。
+func (f *cloudFields) take(attrs []slog.Attr) []slog.Attr {
+	rest := attrs[:0:0]
+	for i := len(attrs) - 1; i >= 0; i-- {
+		a := attrs[i]
+		switch a.Key {
+		case CloudTraceKey:
+			f.trace = firstAttr(f.trace, a)
+		case CloudSpanIDKey:
+			f.span = firstAttr(f.span, a)
+		case CloudTraceSampledKey:
+			f.sampled = firstAttr(f.sampled, a)
+		case CloudLabelsKey:
+			if a.Value.Resolve().Kind() != slog.KindGroup {
+				rest = append(rest, a)
+				continue
+			}
+			group := a.Value.Resolve().Group()
+			for j := len(group) - 1; j >= 0; j-- {
+				f.labels = append(f.labels, slog.String(group[j].Key, group[j].Value.Resolve().String()))
+			}
+		default:
+			rest = append(rest, a)
+		}
+	}
+	// 逆順に走査したので元の順序へ戻します。
+	for i, j := 0, len(rest)-1; i < j; i, j = i+1, j-1 {
+		rest[i], rest[j] = rest[j], rest[i]
+	}
+	return rest
+}
+
+// firstAttr は、まだ取り出していなければ a を採用します。
+func firstAttr(current *slog.Attr, a slog.Attr) *slog.Attr {
+	if current != nil {
+		return current
+	}
+	return &a
+}
+
+// attrs は取り出したフィールドをトップレベルの属性として返します。
+func (f *cloudFields) attrs(projectID string) []slog.Attr {
+	var out []slog.Attr
+	if f.trace != nil {
+		trace := *f.trace
+		if id := trace.Value.Resolve().String(); projectID != "" && !strings.HasPrefix(id, "projects/") {
+			trace.Value = slog.StringValue("projects/" + projectID + "/traces/" + id)
+		}
+		out = append(out, trace)
+	}
+	if f.span != nil {
+		out = append(out, *f.span)
+	}
+	if f.sampled != nil {
+		out = append(out, *f.sampled)
+	}
+	if len(f.labels) > 0 {
+		// 内側から集めたので、外側から順に上書きしてまとめ直します。
+		merged := make([]slog.Attr, 0, len(f.labels))
+		index := map[string]int{}
+		for i := len(f.labels) - 1; i >= 0; i-- {
+			l := f.labels[i]
+			if j, ok := index[l.Key]; ok {
+				merged[j] = l
+				continue
+			}
+			index[l.Key] = len(merged)
+			merged = append(merged, l)
+		}
+		out = append(out, slog.Attr{Key: CloudLabelsKey, Value: slog.GroupValue(merged...)})
+	}
+	return out
+}
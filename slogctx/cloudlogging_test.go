@@ -0,0 +1,173 @@
+package slogctx
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "testdata のゴールデンファイルを書き換える")
+
+// goldenTime はゴールデンファイルに書かれるレコードの時刻です。
+var goldenTime = time.Date(2026, time.July, 25, 6, 4, 5, 0, time.UTC)
+
+// assertGolden は got を testdata/cloudlogging/{name}.golden の内容と比べます。
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", "cloudlogging", name+".golden")
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("出力がゴールデンファイルと異なる (%s)\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+func TestCloudLoggingGolden(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *CloudLoggingOptions
+		log  func(h slog.Handler)
+	}{
+		{
+			name: "severity",
+			opts: &CloudLoggingOptions{Level: slog.LevelDebug},
+			log: func(h slog.Handler) {
+				for _, level := range []slog.Level{
+					slog.LevelDebug, slog.LevelInfo, LevelNotice, slog.LevelWarn,
+					slog.LevelError, LevelCritical, LevelAlert, LevelEmergency,
+				} {
+					handle(h, context.Background(), level, "level "+level.String())
+				}
+			},
+		},
+		{
+			name: "trace_promotion",
+			opts: &CloudLoggingOptions{ProjectID: "my-project"},
+			log: func(h slog.Handler) {
+				ctx := WithTrace(context.Background(), TraceContext{
+					TraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+					SpanID:  "00f067aa0ba902b7",
+					Sampled: true,
+				}, CloudTraceKeys(""))
+				ctx = With(ctx, slog.String("job_id", "job-1"))
+				handle(h.WithAttrs([]slog.Attr{slog.String("component", "pipeline")}).WithGroup("req"),
+					ctx, slog.LevelInfo, "phase started", slog.String("id", "r-1"))
+			},
+		},
+		{
+			name: "labels",
+			log: func(h slog.Handler) {
+				ctx := With(context.Background(), slog.Group(CloudLabelsKey, slog.String("env", "prod"), slog.String("team", "media")))
+				h = h.WithAttrs([]slog.Attr{slog.Group(CloudLabelsKey, slog.String("env", "dev"), slog.String("service", "api"))})
+				handle(h.WithGroup("g"), ctx, slog.LevelWarn, "labeled", slog.Group(CloudLabelsKey, slog.Int("attempt", 2)))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tt.log(NewCloudLoggingHandler(&buf, tt.opts))
+			assertGolden(t, tt.name, buf.Bytes())
+		})
+	}
+}
+
+// handle は時刻を固定したレコードを h へ渡します。
+func handle(h slog.Handler, ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	r := slog.NewRecord(goldenTime, level, msg, 0)
+	r.AddAttrs(attrs...)
+	if h.Enabled(ctx, level) {
+		_ = h.Handle(ctx, r)
+	}
+}
+
+func TestCloudLoggingSourceLocation(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewCloudLoggingHandler(&buf, &CloudLoggingOptions{AddSource: true}))
+
+	_, file, line, _ := runtime.Caller(0)
+	logger.Info("with source")
+
+	entries := decodeLines(t, &buf)
+	if len(entries) != 1 {
+		t.Fatalf("entries = %d, want 1", len(entries))
+	}
+	loc, ok := entries[0][CloudSourceLocationKey].(map[string]any)
+	if !ok {
+		t.Fatalf("%s が出力されていない: %v", CloudSourceLocationKey, entries[0])
+	}
+	if loc["file"] != file || loc["line"] != strconv.Itoa(line+1) {
+		t.Errorf("sourceLocation = %v, want %s:%d", loc, file, line+1)
+	}
+	if _, ok := entries[0][slog.SourceKey]; ok {
+		t.Errorf("source が詰め替えられずに残っている: %v", entries[0])
+	}
+}
+
+func TestCloudLoggingReplaceAttr(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewCloudLoggingHandler(&buf, &CloudLoggingOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			// 詰め替え後のキーで呼ばれること。
+			if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == "secret") {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	logger.Info("msg", slog.String("secret", "x"))
+
+	entries := decodeLines(t, &buf)
+	if len(entries) != 1 {
+		t.Fatalf("entries = %d, want 1", len(entries))
+	}
+	if _, ok := entries[0]["secret"]; ok {
+		t.Errorf("ReplaceAttr で消した属性が残っている: %v", entries[0])
+	}
+	if entries[0]["severity"] != "INFO" || entries[0]["message"] != "msg" {
+		t.Errorf("entry = %v, want severity/message に詰め替え済み", entries[0])
+	}
+}
+
+// NewHandler で包んでも context 属性が二重に出力されないこと。
+func TestCloudLoggingUnderNewHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(NewCloudLoggingHandler(&buf, nil)))
+	logger.InfoContext(With(context.Background(), slog.String("job_id", "job-1")), "msg")
+
+	if n := bytes.Count(buf.Bytes(), []byte(`"job_id"`)); n != 1 {
+		t.Errorf("job_id の出現回数 = %d, want 1: %s", n, buf.String())
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	tests := map[slog.Level]string{
+		slog.LevelDebug - 4: "DEBUG",
+		slog.LevelInfo + 1:  "INFO",
+		slog.LevelWarn - 1:  "NOTICE",
+		slog.LevelWarn + 2:  "WARNING",
+		slog.LevelError + 3: "ERROR",
+		LevelEmergency + 4:  "EMERGENCY",
+	}
+	for level, want := range tests {
+		if got := Severity(level); got != want {
+			t.Errorf("Severity(%v) = %q, want %q", level, got, want)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package slogctx
+
+import (
+	"context"
+	"log/slog"
+)
+
+// AttrSource は属性の出どころです。WithDedup でキーが重複したときの優先順位に使います。
+type AttrSource int
+
+const (
+	// SourceLogger は logger.With（Handler.WithAttrs）で付けた属性です。
+	SourceLogger AttrSource = iota
+	// SourceContext は With で context に積んだ属性と、Extractor が取り出した属性です。
+	SourceContext
+	// SourceCall はログ呼び出しの引数に渡した属性です。
+	SourceCall
+)
+
+// WithDedup は、同じキーの属性を 1 つにまとめてから委譲先へ渡します。
+//
+// logger.With、With、呼び出し時の引数で同じキーを指定すると、標準の JSON ハンドラーは
+// 重複したフィールドをそのまま出力し、多くの取り込み側で後の値が捨てられたり取り込みに
+// 失敗したりします。WithDedup を指定すると、precedence に先に挙げた出どころの値を残します。
+// 省略した場合は SourceCall、SourceContext、SourceLogger の順です。挙げなかった出どころは
+// 最も優先度が低くなり、同じ出どころの中では後に指定した値が勝ちます。WrapError でエラーに
+// 持たせた属性は、そのエラーの直後に指定したものとして扱います。
+//
+// 重複はグループごとに判定します。logger.WithGroup("req") の内側の "id" とトップレベルの "id" は
+// 別のキーで、同じ名前のグループ同士は中身をまとめて 1 つのグループにします。残った値は、
+// そのキーが最初に現れた位置に出力します。
+//
+// まとめるために logger.With の属性を委譲先の WithAttrs へ渡さず、レコードごとに組み立て直します。
+// 出力を丸ごと溜め込むことはありませんが、委譲先による属性の事前整形は効かなくなります。
+func WithDedup(precedence ...AttrSource) Option {
+	if len(precedence) == 0 {
+		precedence = []AttrSource{SourceCall, SourceContext, SourceLogger}
+	}
+	var ranks [SourceCall + 1]int
+	for i, src := range precedence {
+		if src < 0 || src > SourceCall || ranks[src] != 0 {
+			continue
+		}
+		ranks[src] = len(precedence) - i
+	}
+	return func(o *handlerOptions) {
+		o.dedup = &ranks
+	}
+}
+
+// handleDedup は、logger.With の属性と context 属性をレコードの属性とまとめたうえで委譲先へ渡します。
+func (h *handler) handleDedup(ctx context.Context, record slog.Record) error {
+	ranks := h.opts.dedup
+	root := &dedupSet{}
+	current := root
+	for _, goa := range h.goas {
+		if goa.group != "" {
+			current = current.group(goa.group, ranks[SourceLogger])
+			continue
+		}
+		current.addAll(goa.attrs, ranks[SourceLogger])
+	}
+
+	if record.NumAttrs() > 0 {
+		attrs, _ := expandErrorAttrs(recordAttrs(record), h.opts.errorStack)
+		current.addAll(attrs, ranks[SourceCall])
+	}
+	attrs := attrsFrom(ctx)
+	if len(h.opts.extractors) > 0 {
+		extracted, _ := expandErrorAttrs(extract(ctx, h.opts.extractors, attrs), h.opts.errorStack)
+		current.addAll(extracted, ranks[SourceContext])
+	}
+	if len(attrs) > 0 {
+		expanded, _ := expandErrorAttrs(attrs, h.opts.errorStack)
+		current.addAll(expanded, ranks[SourceContext])
+		ctx = withoutAttrs(ctx)
+	}
+
+	return h.Handler.Handle(ctx, rebuildRecord(record, root.attrs()))
+}
+
+// dedupSet は 1 つの階層に属する、キーが重複しない属性の並びです。
+// 1 件のログの属性は多くても数十なので、マップを作らず線形に探します。
+type dedupSet struct {
+	entries []dedupEntry
+}
+
+// dedupEntry はキー 1 つ分の属性です。group が nil でなければグループです。
+type dedupEntry struct {
+	key   string
+	value slog.Value
+	group *dedupSet
+	rank  int
+}
+
+func (s *dedupSet) find(key string) *dedupEntry {
+	for i := range s.entries {
+		if s.entries[i].key == key {
+			return &s.entries[i]
+		}
+	}
+	return nil
+}
+
+func (s *dedupSet) addAll(attrs []slog.Attr, rank int) {
+	for _, a := range attrs {
+		s.add(a, rank)
+	}
+}
+
+// add は a を追加します。同じキーがあれば、優先度が同じか高い場合だけ置き換えます。
+// グループ同士は置き換えずに中身をまとめます。
+func (s *dedupSet) add(a slog.Attr, rank int) {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() != slog.KindGroup {
+		if a.Key == "" {
+			return
+		}
+		e := s.find(a.Key)
+		if e == nil {
+			s.entries = append(s.entries, dedupEntry{key: a.Key, value: a.Value, rank: rank})
+			return
+		}
+		if rank >= e.rank {
+			e.value, e.group, e.rank = a.Value, nil, rank
+		}
+		return
+	}
+
+	members := a.Value.Group()
+	if len(members) == 0 {
+		return
+	}
+	if a.Key == "" {
+		s.addAll(members, rank)
+		return
+	}
+	if e := s.find(a.Key); e != nil && e.group == nil && rank < e.rank {
+		return
+	}
+	s.group(a.Key, rank).addAll(members, rank)
+}
+
+// group は key のグループを返します。無ければ作り、同じキーの値があればグループで置き換えます。
+func (s *dedupSet) group(key string, rank int) *dedupSet {
+	e := s.find(key)
+	if e == nil {
+		s.entries = append(s.entries, dedupEntry{key: key})
+		e = &s.entries[len(s.entries)-1]
+	}
+	if e.group == nil {
+		e.value, e.group = slog.Value{}, &dedupSet{}
+	}
+	e.rank = max(e.rank, rank)
+	return e.group
+}
+
+// attrs はまとめた結果を属性へ戻します。中身の無いグループは除きます。
+func (s *dedupSet) attrs() []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(s.entries))
+	for _, e := range s.entries {
+		if e.group == nil {
+			attrs = append(attrs, slog.Attr{Key: e.key, Value: e.value})
+			continue
+		}
+		if members := e.group.attrs(); len(members) > 0 {
+			attrs = append(attrs, slog.Attr{Key: e.key, Value: slog.GroupValue(members...)})
+		}
+	}
+	return attrs
+}
@@ -0,0 +1,140 @@
+package slogctx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/slogtest"
+)
+
+// newDedupLogger は、時刻を除いた JSON を buf へ書く WithDedup 付きのロガーを返します。
+// 重複したフィールドはマップへ復号すると消えるため、テストでは出力を文字列のまま比べます。
+func newDedupLogger(buf *bytes.Buffer, opts ...Option) *slog.Logger {
+	base := slog.NewJSONHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	return slog.New(NewHandler(base, opts...))
+}
+
+func TestDedupSlogtest(t *testing.T) {
+	var buf bytes.Buffer
+	slogtest.Run(t, func(*testing.T) slog.Handler {
+		buf.Reset()
+		return NewHandler(slog.NewJSONHandler(&buf, nil), WithDedup())
+	}, func(t *testing.T) map[string]any {
+		var m map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+			t.Fatal(err)
+		}
+		return m
+	})
+}
+
+func TestDedupPrecedence(t *testing.T) {
+	ctx := With(context.Background(), slog.String("id", "context"), slog.String("job_id", "job-1"))
+
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			"既定は呼び出し時、context、logger の順", []Option{WithDedup()},
+			`{"level":"INFO","msg":"m","id":"call","service":"api","job_id":"job-1"}`,
+		},
+		{
+			"優先順位を変更できる", []Option{WithDedup(SourceLogger, SourceContext)},
+			`{"level":"INFO","msg":"m","id":"logger","service":"api","job_id":"job-1"}`,
+		},
+		{
+			"挙げなかった出どころは最も弱い", []Option{WithDedup(SourceContext)},
+			`{"level":"INFO","msg":"m","id":"context","service":"api","job_id":"job-1"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := newDedupLogger(&buf, tt.opts...).With("id", "logger", "service", "api")
+			logger.InfoContext(ctx, "m", "id", "call")
+			if got := strings.TrimSpace(buf.String()); got != tt.want {
+				t.Errorf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDedupGroups(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newDedupLogger(&buf, WithDedup()).
+		With("id", "top", slog.Group("req", slog.String("method", "GET"), slog.String("path", "/a"))).
+		WithGroup("req").
+		With("id", "logger")
+
+	ctx := With(context.Background(), slog.String("id", "context"))
+	logger.InfoContext(ctx, "m",
+		slog.Group("", slog.String("path", "/b")),
+		slog.Group("empty"),
+		"status", 200,
+		"status", 201,
+	)
+
+	// グループ内外の同名キーは別物として扱い、同名のグループは 1 つにまとめること。
+	want := `{"level":"INFO","msg":"m","id":"top","req":{"method":"GET","path":"/b","id":"context","status":201}}`
+	if got := strings.TrimSpace(buf.String()); got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestDedupGroupAndScalar(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newDedupLogger(&buf, WithDedup()).With(slog.Group("user", slog.String("id", "u1")))
+
+	logger.Info("m", "user", "anonymous")
+	logger.With("user", "anonymous").Info("m", slog.Group("user", slog.String("id", "u2")))
+
+	want := `{"level":"INFO","msg":"m","user":"anonymous"}` + "\n" +
+		`{"level":"INFO","msg":"m","user":{"id":"u2"}}`
+	if got := strings.TrimSpace(buf.String()); got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestDedupExpandsErrorAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newDedupLogger(&buf, WithDedup())
+	err := WrapError(io.EOF, slog.String("object", "a.txt"))
+
+	// エラーの属性はエラーの直後に展開されるため、同じ呼び出しの前の引数より後に指定したものとして勝つこと。
+	logger.Info("m", "object", "b.txt", "error", err)
+	want := `{"level":"INFO","msg":"m","object":"a.txt","error":"EOF"}`
+	if got := strings.TrimSpace(buf.String()); got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func benchmarkHandler(b *testing.B, opts ...Option) {
+	logger := slog.New(NewHandler(slog.NewJSONHandler(io.Discard, nil), opts...)).
+		With("service", "api", "version", "1.2.3").
+		WithGroup("req").
+		With("method", "GET")
+	ctx := With(context.Background(), slog.String("request_id", "req-1"), slog.String("job_id", "job-1"))
+
+	b.ReportAllocs()
+	for b.Loop() {
+		logger.InfoContext(ctx, "http request", "status", 200, "path", "/objects", "request_id", "req-2")
+	}
+}
+
+func BenchmarkHandler(b *testing.B) {
+	b.Run("plain", func(b *testing.B) { benchmarkHandler(b) })
+	b.Run("dedup", func(b *testing.B) { benchmarkHandler(b, WithDedup()) })
+}
@@ -0,0 +1,149 @@
+package slogctx
+
+import (
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// maxStackDepth は WrapError が記録する呼び出し履歴の最大段数です。
+const maxStackDepth = 32
+
+// attrError は、ログ属性とラップ時点の呼び出し履歴を持つエラーです。
+// Error と Unwrap は元のエラーをそのまま見せるため、errors.Is / errors.As の判定は変わりません。
+type attrError struct {
+	err   error
+	attrs []slog.Attr
+	stack []uintptr
+}
+
+func (e *attrError) Error() string { return e.err.Error() }
+
+func (e *attrError) Unwrap() error { return e.err }
+
+// WrapError は err にログ属性を持たせます。err が nil なら nil を返します。
+//
+// オブジェクトキーや試行回数のように、エラーが起きた深い層でしか分からない値を、
+// 何層も上でエラーがログに出されるときまで運ぶためのものです。NewHandler で包んだ
+// ハンドラーは、エラーを値に持つ属性を見つけると、ここで持たせた属性を隣に展開します。
+// メッセージは err のまま変えません。
+func WrapError(err error, attrs ...slog.Attr) error {
+	if err == nil {
+		return nil
+	}
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(2, pcs)
+	return &attrError{err: err, attrs: attrs, stack: pcs[:n]}
+}
+
+// ErrorAttrs は、WrapError で err に持たせた属性を返します。
+//
+// errors.Join でまとめたエラーも含めてラップの連鎖をすべて辿り、外側で持たせたものから順に並べます。
+// 同じキーが複数の層にある場合は、最も外側（最後にラップした層）の値だけを残します。
+func ErrorAttrs(err error) []slog.Attr {
+	var attrs []slog.Attr
+	seen := map[string]struct{}{}
+	walkAttrErrors(err, func(e *attrError) {
+		for _, a := range e.attrs {
+			if _, ok := seen[a.Key]; ok {
+				continue
+			}
+			seen[a.Key] = struct{}{}
+			attrs = append(attrs, a)
+		}
+	})
+	return attrs
+}
+
+// errorStack は、WrapError で最も内側（エラーの発生源に最も近い層）に記録した呼び出し履歴を整形します。
+func errorStack(err error) string {
+	var stack []uintptr
+	walkAttrErrors(err, func(e *attrError) {
+		stack = e.stack
+	})
+	if len(stack) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	frames := runtime.CallersFrames(stack)
+	for {
+		frame, more := frames.Next()
+		b.WriteString(frame.Function)
+		b.WriteString("\n\t")
+		b.WriteString(frame.File)
+		b.WriteString(":")
+		b.WriteString(strconv.Itoa(frame.Line))
+		b.WriteString("\n")
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
+
+// walkAttrErrors は、err のラップの連鎖を深さ優先で辿り、attrError を外側から順に fn へ渡します。
+// errors.Join のような複数のエラーをまとめたものは、まとめた順に辿ります。
+func walkAttrErrors(err error, fn func(*attrError)) {
+	for err != nil {
+		if e, ok := err.(*attrError); ok {
+			fn(e)
+		}
+		switch u := err.(type) {
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, inner := range u.Unwrap() {
+				walkAttrErrors(inner, fn)
+			}
+			return
+		default:
+			return
+		}
+	}
+}
+
+// expandErrorAttrs は、エラーを値に持つ属性の直後に、そのエラーが持つ属性を展開します。
+// 展開するものが無ければ attrs をそのまま返し、ok は false です。
+func expandErrorAttrs(attrs []slog.Attr, withStack bool) (expanded []slog.Attr, ok bool) {
+	for i, a := range attrs {
+		extra := errorAttrsOf(a, withStack)
+		if len(extra) == 0 {
+			if ok {
+				expanded = append(expanded, a)
+			}
+			continue
+		}
+		if !ok {
+			expanded = append(make([]slog.Attr, 0, len(attrs)+len(extra)), attrs[:i]...)
+			ok = true
+		}
+		expanded = append(expanded, a)
+		expanded = append(expanded, extra...)
+	}
+	if !ok {
+		return attrs, false
+	}
+	return expanded, true
+}
+
+// errorAttrsOf は、属性の値がエラーならその属性と呼び出し履歴（withStack のとき）を返します。
+// 呼び出し履歴のキーは "{属性のキー}_stack" です。
+func errorAttrsOf(a slog.Attr, withStack bool) []slog.Attr {
+	if a.Value.Kind() != slog.KindAny {
+		return nil
+	}
+	err, ok := a.Value.Any().(error)
+	if !ok {
+		return nil
+	}
+
+	extra := ErrorAttrs(err)
+	if withStack {
+		if stack := errorStack(err); stack != "" {
+			extra = append(extra, slog.String(a.Key+"_stack", stack))
+		}
+	}
+	return extra
+}
@@ -0,0 +1,90 @@
+package slogctx
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestWrapErrorIsTransparent(t *testing.T) {
+	if WrapError(nil, slog.Int("attempt", 1)) != nil {
+		t.Error("WrapError(nil) が nil を返さない")
+	}
+
+	err := WrapError(io.EOF, slog.Int("attempt", 1))
+	if err.Error() != io.EOF.Error() {
+		t.Errorf("Error() = %q, want %q", err.Error(), io.EOF.Error())
+	}
+	if !errors.Is(err, io.EOF) {
+		t.Error("errors.Is で元のエラーを判定できない")
+	}
+}
+
+func TestErrorAttrs(t *testing.T) {
+	inner := WrapError(errors.New("not found"), slog.String("object", "a.mp4"), slog.Int("attempt", 1))
+	outer := WrapError(fmt.Errorf("download: %w", inner), slog.Int("attempt", 3))
+	other := WrapError(errors.New("timeout"), slog.Int("status", 504))
+	joined := errors.Join(outer, other, errors.New("plain"))
+
+	got := ErrorAttrs(joined)
+	want := []slog.Attr{slog.Int("attempt", 3), slog.String("object", "a.mp4"), slog.Int("status", 504)}
+	if len(got) != len(want) {
+		t.Fatalf("ErrorAttrs() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("ErrorAttrs()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	if got := ErrorAttrs(errors.New("plain")); got != nil {
+		t.Errorf("ErrorAttrs(plain) = %v, want nil", got)
+	}
+}
+
+func TestHandlerExpandsErrorAttrs(t *testing.T) {
+	rec := NewRecorder()
+	logger := slog.New(NewHandler(rec))
+
+	err := fmt.Errorf("render: %w", WrapError(errors.New("boom"), slog.String("object", "a.mp4")))
+	logger.Error("failed", slog.Any("error", err))
+
+	e := rec.RequireLogged(t, slog.LevelError, "failed", slog.String("object", "a.mp4"))
+	if _, ok := e.AttrValue("error_stack"); ok {
+		t.Error("WithErrorStack なしで呼び出し履歴が出力された")
+	}
+
+	// logger.With で積んだエラーも展開されること。
+	rec.Reset()
+	logger.With("error", err).Error("failed")
+	rec.RequireLogged(t, slog.LevelError, "failed", slog.String("object", "a.mp4"))
+}
+
+func TestHandlerErrorStack(t *testing.T) {
+	rec := NewRecorder()
+	logger := slog.New(NewHandler(rec, WithErrorStack()))
+
+	logger.Error("failed", slog.Any("cause", WrapError(errors.New("boom"))))
+
+	e := rec.RequireLogged(t, slog.LevelError, "failed")
+	stack, ok := e.AttrValue("cause_stack")
+	if !ok {
+		t.Fatalf("呼び出し履歴が出力されていない: %v", e.Attrs)
+	}
+	if !strings.Contains(stack.String(), "TestHandlerErrorStack") {
+		t.Errorf("呼び出し履歴にラップした関数が含まれない:\n%s", stack)
+	}
+}
+
+func TestHandlerLeavesPlainErrors(t *testing.T) {
+	rec := NewRecorder()
+	slog.New(NewHandler(rec, WithErrorStack())).Error("failed", slog.Any("error", errors.New("plain")), slog.Int("n", 1))
+
+	e := rec.RequireLogged(t, slog.LevelError, "failed")
+	if len(e.Attrs) != 2 {
+		t.Errorf("Attrs = %v, want error と n のみ", e.Attrs)
+	}
+}
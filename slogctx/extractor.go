@@ -0,0 +1,66 @@
+package slogctx
+
+import (
+	"context"
+	"log/slog"
+)
+
+// ExtractorPanicKey は、Extractor が panic したときに、その値を載せる属性のキーです。
+// slog が不正なキーに使う "!BADKEY" にならい、通常の属性と衝突しない名前にしています。
+const ExtractorPanicKey = "!EXTRACTOR_PANIC"
+
+// Extractor は、With を使わずに context に入っている値からログ属性を取り出す関数です。
+// 他のパッケージが独自のキーで context に入れたスパンや認証主体、テナント ID などを、
+// With で積み直さずにログへ載せるために使います。値が無ければ nil を返してください。
+type Extractor func(ctx context.Context) []slog.Attr
+
+// WithExtractor は、Handle のたびに呼ぶ Extractor を登録します。複数回指定すると登録順に呼びます。
+//
+// 取り出した属性は With で積んだ属性より前に並びます。キーが重複した場合は、
+// With で積んだ属性を優先し、次に先に登録した Extractor を優先して、後のものは捨てます。
+// 明示的に積んだ値を、暗黙に取り出した値で上書きしないためです。
+//
+// Extractor が panic してもログの出力は止めません。その Extractor の属性は捨て、
+// 代わりに panic の値を ExtractorPanicKey の属性として載せます。
+func WithExtractor(fn Extractor) Option {
+	return func(o *handlerOptions) {
+		if fn != nil {
+			o.extractors = append(o.extractors, fn)
+		}
+	}
+}
+
+// extract は extractors を順に呼び、stored（With で積んだ属性）と重複しない属性を返します。
+func extract(ctx context.Context, extractors []Extractor, stored []slog.Attr) []slog.Attr {
+	seen := make(map[string]struct{}, len(stored))
+	for _, a := range stored {
+		seen[a.Key] = struct{}{}
+	}
+
+	var attrs []slog.Attr
+	for _, fn := range extractors {
+		extracted, recovered := callExtractor(ctx, fn)
+		if recovered != nil {
+			attrs = append(attrs, slog.Any(ExtractorPanicKey, recovered))
+			continue
+		}
+		for _, a := range extracted {
+			if _, ok := seen[a.Key]; ok {
+				continue
+			}
+			seen[a.Key] = struct{}{}
+			attrs = append(attrs, a)
+		}
+	}
+	return attrs
+}
+
+// callExtractor は fn を呼び、panic したときはその値を返します。
+func callExtractor(ctx context.Context, fn Extractor) (attrs []slog.Attr, recovered any) {
+	defer func() {
+		if r := recover(); r != nil {
+			attrs, recovered = nil, r
+		}
+	}()
+	return fn(ctx), nil
+}
@@ -0,0 +1,85 @@
+package slogctx
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+)
+
+// tenantKey は、別のパッケージが context に入れた値を模したキーです。
+type tenantKey struct{}
+
+func tenantExtractor(ctx context.Context) []slog.Attr {
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+	if !ok {
+		return nil
+	}
+	return []slog.Attr{slog.String("tenant", tenant), slog.String("job_id", "from-extractor")}
+}
+
+func TestExtractorAddsAttrs(t *testing.T) {
+	rec := NewRecorder()
+	logger := slog.New(NewHandler(rec, WithExtractor(tenantExtractor)))
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	logger.InfoContext(ctx, "msg")
+	rec.RequireLogged(t, slog.LevelInfo, "msg", slog.String("tenant", "acme"), slog.String("job_id", "from-extractor"))
+
+	// 値が無ければ何も足さないこと。
+	rec.Reset()
+	logger.Info("plain")
+	if e := rec.RequireLogged(t, slog.LevelInfo, "plain"); len(e.Attrs) != 0 {
+		t.Errorf("Attrs = %v, want 空", e.Attrs)
+	}
+}
+
+func TestExtractorOrderAndDedup(t *testing.T) {
+	rec := NewRecorder()
+	second := func(context.Context) []slog.Attr {
+		return []slog.Attr{slog.String("tenant", "second"), slog.String("region", "tokyo")}
+	}
+	logger := slog.New(NewHandler(rec, WithExtractor(tenantExtractor), WithExtractor(second), WithExtractor(nil)))
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	ctx = With(ctx, slog.String("job_id", "job-1"))
+	logger.InfoContext(ctx, "msg")
+
+	e := rec.RequireLogged(t, slog.LevelInfo, "msg")
+	var keys []string
+	for _, a := range e.Attrs {
+		keys = append(keys, a.Key+"="+a.Value.String())
+	}
+	// With の属性が優先され、先に登録した Extractor が後のものに勝ち、With の属性は最後に並ぶこと。
+	want := []string{"tenant=acme", "region=tokyo", "job_id=job-1"}
+	if len(keys) != len(want) {
+		t.Fatalf("attrs = %v, want %v", keys, want)
+	}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Fatalf("attrs = %v, want %v", keys, want)
+		}
+	}
+}
+
+func TestExtractorPanicIsGuarded(t *testing.T) {
+	rec := NewRecorder()
+	broken := func(context.Context) []slog.Attr { panic("broken extractor") }
+	logger := slog.New(NewHandler(rec, WithExtractor(broken), WithExtractor(tenantExtractor)))
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	logger.InfoContext(ctx, "msg")
+
+	rec.RequireLogged(t, slog.LevelInfo, "msg",
+		slog.String(ExtractorPanicKey, "broken extractor"),
+		slog.String("tenant", "acme"),
+	)
+}
+
+// logger.With で派生したあとも Extractor が引き継がれること。
+func TestExtractorSurvivesWithAttrs(t *testing.T) {
+	rec := NewRecorder()
+	logger := slog.New(NewHandler(rec, WithExtractor(tenantExtractor))).With("component", "api").WithGroup("g")
+
+	logger.InfoContext(context.WithValue(context.Background(), tenantKey{}, "acme"), "msg")
+	rec.RequireLogged(t, slog.LevelInfo, "msg", slog.String("component", "api"), slog.String("g.tenant", "acme"))
+}
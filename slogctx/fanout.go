@@ -0,0 +1,105 @@
+package slogctx
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// Sink は NewFanout が振り分ける出力先の 1 つです。
+type Sink struct {
+	// Handler は出力先のハンドラーです。必須です。
+	Handler slog.Handler
+
+	// MinLevel は、このシンクへ渡す最小レベルです。nil なら Handler の Enabled だけで判定します。
+	// Handler 自身のレベル設定より低くしても、Handler 側で捨てられます。
+	MinLevel slog.Leveler
+
+	// Match は、レベル判定を通ったレコードをさらに絞り込む述語です。nil ならすべて渡します。
+	// レコードの属性には logger.With で積んだものは含まれません（シンク側で保持しているため）。
+	Match func(ctx context.Context, record slog.Record) bool
+}
+
+// enabled はレベルだけで、このシンクがレコードを受け取りうるかを返します。
+func (s Sink) enabled(ctx context.Context, level slog.Level) bool {
+	if s.MinLevel != nil && level < s.MinLevel.Level() {
+		return false
+	}
+	return s.Handler.Enabled(ctx, level)
+}
+
+// NewFanout は、1 つのレコードを複数のシンクへ振り分けるハンドラーを返します。
+// エラーは標準出力の JSON と通知用のハンドラーへ、デバッグはローカルのテキスト出力だけへ、
+// といった出し分けに使います。Handler が nil のシンクは無視します。
+//
+// context 属性の付与は、NewHandler(NewFanout(...)) のように外側で一度だけ行ってください。
+// 各シンクを NewHandler で包んでいても、外側で付与済みの属性が重ねて付くことはありません。
+//
+// 各シンクのエラーは errors.Join でまとめて返し、1 つのシンクが失敗しても残りへの出力は続けます。
+func NewFanout(sinks ...Sink) slog.Handler {
+	kept := make([]Sink, 0, len(sinks))
+	for _, s := range sinks {
+		if s.Handler != nil {
+			kept = append(kept, s)
+		}
+	}
+	return &fanout{sinks: kept}
+}
+
+// fanout はレコードを複数のシンクへ複製して渡す slog.Handler です。
+type fanout struct {
+	sinks []Sink
+}
+
+// Enabled は、いずれかのシンクがそのレベルを受け取るなら true を返します。
+func (f *fanout) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, s := range f.sinks {
+		if s.enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle は条件に合うシンクへレコードの複製を渡します。
+// 属性の格納領域を共有したまま複数のハンドラーへ渡すと、一方の追加が他方へ漏れうるため複製します。
+func (f *fanout) Handle(ctx context.Context, record slog.Record) error {
+	var errs []error
+	for _, s := range f.sinks {
+		if !s.enabled(ctx, record.Level) {
+			continue
+		}
+		if s.Match != nil && !s.Match(ctx, record) {
+			continue
+		}
+		if err := s.Handler.Handle(ctx, record.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithAttrs / WithGroup は各シンクのハンドラーを包み直します。
+// レベルと述語はシンクごとにそのまま引き継ぎます。
+func (f *fanout) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return f
+	}
+	return f.derive(func(h slog.Handler) slog.Handler { return h.WithAttrs(attrs) })
+}
+
+func (f *fanout) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return f
+	}
+	return f.derive(func(h slog.Handler) slog.Handler { return h.WithGroup(name) })
+}
+
+func (f *fanout) derive(wrap func(slog.Handler) slog.Handler) *fanout {
+	sinks := make([]Sink, len(f.sinks))
+	for i, s := range f.sinks {
+		s.Handler = wrap(s.Handler)
+		sinks[i] = s
+	}
+	return &fanout{sinks: sinks}
+}
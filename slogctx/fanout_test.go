@@ -0,0 +1,154 @@
+package slogctx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// errHandler は Handle が常に失敗するハンドラーです。
+type errHandler struct {
+	slog.Handler
+	err error
+}
+
+func (h errHandler) Handle(context.Context, slog.Record) error { return h.err }
+
+func TestFanoutRoutesByLevel(t *testing.T) {
+	var stdout, alert, local bytes.Buffer
+	logger := slog.New(NewHandler(NewFanout(
+		Sink{Handler: slog.NewJSONHandler(&stdout, nil), MinLevel: slog.LevelInfo},
+		Sink{Handler: slog.NewJSONHandler(&alert, nil), MinLevel: slog.LevelError},
+		Sink{Handler: slog.NewJSONHandler(&local, &slog.HandlerOptions{Level: slog.LevelDebug}), MinLevel: slog.LevelDebug},
+	)))
+
+	ctx := With(context.Background(), slog.String("job_id", "job-1"))
+	logger.DebugContext(ctx, "debug")
+	logger.ErrorContext(ctx, "failed")
+
+	if got := decodeLines(t, &stdout); len(got) != 1 || got[0]["msg"] != "failed" {
+		t.Errorf("stdout = %v, want failed のみ", got)
+	}
+	if got := decodeLines(t, &alert); len(got) != 1 || got[0]["msg"] != "failed" {
+		t.Errorf("alert = %v, want failed のみ", got)
+	}
+	got := decodeLines(t, &local)
+	if len(got) != 2 {
+		t.Fatalf("local entries = %d, want 2", len(got))
+	}
+	for _, entry := range got {
+		if entry["job_id"] != "job-1" {
+			t.Errorf("local entry = %v, want job_id を含む", entry)
+		}
+	}
+}
+
+func TestFanoutMatch(t *testing.T) {
+	var all, audit bytes.Buffer
+	logger := slog.New(NewFanout(
+		Sink{Handler: slog.NewJSONHandler(&all, nil)},
+		Sink{
+			Handler: slog.NewJSONHandler(&audit, nil),
+			Match: func(_ context.Context, r slog.Record) bool {
+				matched := false
+				r.Attrs(func(a slog.Attr) bool {
+					matched = a.Key == "audit" && a.Value.Bool()
+					return !matched
+				})
+				return matched
+			},
+		},
+	))
+
+	logger.Info("plain")
+	logger.Info("login", slog.Bool("audit", true))
+
+	if got := decodeLines(t, &all); len(got) != 2 {
+		t.Errorf("all entries = %d, want 2", len(got))
+	}
+	if got := decodeLines(t, &audit); len(got) != 1 || got[0]["msg"] != "login" {
+		t.Errorf("audit = %v, want login のみ", got)
+	}
+}
+
+func TestFanoutWithAttrsAndGroup(t *testing.T) {
+	var a, b bytes.Buffer
+	logger := slog.New(NewFanout(
+		Sink{Handler: slog.NewJSONHandler(&a, nil)},
+		Sink{Handler: slog.NewJSONHandler(&b, nil)},
+	)).With("component", "pipeline").WithGroup("req")
+
+	logger.Info("msg", slog.String("id", "r-1"))
+
+	for name, buf := range map[string]*bytes.Buffer{"a": &a, "b": &b} {
+		entries := decodeLines(t, buf)
+		if len(entries) != 1 {
+			t.Fatalf("%s entries = %d, want 1", name, len(entries))
+		}
+		if entries[0]["component"] != "pipeline" {
+			t.Errorf("%s component = %v, want pipeline", name, entries[0]["component"])
+		}
+		group, ok := entries[0]["req"].(map[string]any)
+		if !ok || group["id"] != "r-1" {
+			t.Errorf("%s req = %v, want req.id = r-1", name, entries[0]["req"])
+		}
+	}
+}
+
+// シンク側を NewHandler で包んでいても、context 属性が二重に付かないこと。
+func TestFanoutInjectsContextAttrsOnce(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(NewFanout(
+		Sink{Handler: NewHandler(slog.NewTextHandler(&buf, nil))},
+	)))
+
+	logger.InfoContext(With(context.Background(), slog.String("job_id", "job-1")), "msg")
+
+	if n := bytes.Count(buf.Bytes(), []byte("job_id=")); n != 1 {
+		t.Errorf("job_id の出現回数 = %d, want 1: %s", n, buf.String())
+	}
+}
+
+func TestFanoutJoinsErrors(t *testing.T) {
+	errA := errors.New("sink a")
+	errB := errors.New("sink b")
+	var buf bytes.Buffer
+	h := NewFanout(
+		Sink{Handler: errHandler{Handler: slog.NewJSONHandler(io.Discard, nil), err: errA}},
+		Sink{Handler: slog.NewJSONHandler(&buf, nil)},
+		Sink{Handler: errHandler{Handler: slog.NewJSONHandler(io.Discard, nil), err: errB}},
+		Sink{}, // Handler が nil のシンクは無視される。
+	)
+
+	err := h.Handle(context.Background(), slog.NewRecord(time.Time{}, slog.LevelInfo, "msg", 0))
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Errorf("Handle() error = %v, want errA と errB の両方", err)
+	}
+	// 失敗したシンクがあっても、残りのシンクへの出力は続くこと。
+	if buf.Len() == 0 {
+		t.Error("失敗しなかったシンクへ出力されていない")
+	}
+}
+
+func TestFanoutEnabled(t *testing.T) {
+	h := NewFanout(
+		Sink{Handler: slog.NewJSONHandler(&bytes.Buffer{}, nil), MinLevel: slog.LevelWarn},
+		Sink{Handler: slog.NewJSONHandler(&bytes.Buffer{}, &slog.HandlerOptions{Level: slog.LevelError})},
+	)
+
+	tests := map[slog.Level]bool{
+		slog.LevelDebug: false,
+		slog.LevelInfo:  false,
+		slog.LevelWarn:  true,
+		slog.LevelError: true,
+	}
+	for level, want := range tests {
+		if got := h.Enabled(context.Background(), level); got != want {
+			t.Errorf("Enabled(%v) = %v, want %v", level, got, want)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package slogctx
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultComponentKey は、LevelRegistry がコンポーネントを見分ける属性キーの既定値です。
+const DefaultComponentKey = "component"
+
+// parseLevelToken は "debug" や "INFO+2" のようなレベル表記を解釈します。
+// 名前の大文字小文字は区別せず、WARN の別名として WARNING も受け付けます。
+func parseLevelToken(raw string) (slog.Level, error) {
+	token := strings.ToUpper(strings.TrimSpace(raw))
+	name, offset := token, ""
+	if i := strings.IndexAny(token, "+-"); i > 0 {
+		name, offset = token[:i], token[i:]
+	}
+
+	var level slog.Level
+	switch name {
+	case "DEBUG":
+		level = slog.LevelDebug
+	case "INFO":
+		level = slog.LevelInfo
+	case "WARN", "WARNING":
+		level = slog.LevelWarn
+	case "ERROR":
+		level = slog.LevelError
+	default:
+		return 0, fmt.Errorf("unknown log level %q", raw)
+	}
+
+	if offset != "" {
+		n, err := strconv.Atoi(offset)
+		if err != nil {
+			return 0, fmt.Errorf("invalid log level offset %q", raw)
+		}
+		level += slog.Level(n)
+	}
+	return level, nil
+}
+
+// LevelSpec はコンポーネントごとのレベル指定です。
+type LevelSpec struct {
+	// Default は、Components に無いコンポーネントと、コンポーネントを持たないログのレベルです。
+	Default slog.Level
+
+	// Components はコンポーネント名ごとのレベルです。
+	Components map[string]slog.Level
+}
+
+// ParseLevelSpec は "info,db=debug,http=warn" のようなレベル指定を解釈します。
+//
+// 名前の無い要素が既定のレベルで、省略すると Info です。各レベルは ParseLevel と同じ表記に加えて
+// "INFO+2" のようなオフセットを受け付けます。ParseLevel と違い、未知の値や重複した指定は
+// 黙って Info にせずエラーにします。設定の誤りで本番のログが消えたり溢れたりするのを防ぐためです。
+func ParseLevelSpec(spec string) (LevelSpec, error) {
+	parsed := LevelSpec{Default: slog.LevelInfo, Components: map[string]slog.Level{}}
+	hasDefault := false
+
+	for item := range strings.SplitSeq(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		component, raw, hasComponent := strings.Cut(item, "=")
+		level, err := parseLevelToken(raw)
+		if !hasComponent {
+			level, err = parseLevelToken(component)
+		}
+		if err != nil {
+			return LevelSpec{}, fmt.Errorf("parse level spec %q: %w", spec, err)
+		}
+
+		if !hasComponent {
+			if hasDefault {
+				return LevelSpec{}, fmt.Errorf("parse level spec %q: default level specified twice", spec)
+			}
+			parsed.Default, hasDefault = level, true
+			continue
+		}
+
+		component = strings.TrimSpace(component)
+		if component == "" {
+			return LevelSpec{}, fmt.Errorf("parse level spec %q: empty component name", spec)
+		}
+		if _, ok := parsed.Components[component]; ok {
+			return LevelSpec{}, fmt.Errorf("parse level spec %q: component %q specified twice", spec, component)
+		}
+		parsed.Components[component] = level
+	}
+
+	return parsed, nil
+}
+
+// LevelRegistry は、コンポーネントごとのレベルを実行中に変更できるよう *slog.LevelVar で保持します。
+// NewLevelHandler と組み合わせて使います。並行に使っても安全です。
+type LevelRegistry struct {
+	key string
+	def slog.LevelVar
+
+	mu   sync.RWMutex
+	vars map[string]*slog.LevelVar
+}
+
+// NewLevelRegistry は、key の属性でコンポーネントを見分ける LevelRegistry を返します。
+// key が空なら DefaultComponentKey を使います。既定のレベルは Info です。
+func NewLevelRegistry(key string) *LevelRegistry {
+	if key == "" {
+		key = DefaultComponentKey
+	}
+	return &LevelRegistry{key: key, vars: map[string]*slog.LevelVar{}}
+}
+
+// Default は既定のレベルを返します。HandlerOptions.Level にも渡せます。
+func (r *LevelRegistry) Default() *slog.LevelVar {
+	return &r.def
+}
+
+// Set はコンポーネントのレベルを設定します。
+func (r *LevelRegistry) Set(component string, level slog.Level) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.setLocked(component, level)
+}
+
+func (r *LevelRegistry) setLocked(component string, level slog.Level) {
+	v, ok := r.vars[component]
+	if !ok {
+		v = &slog.LevelVar{}
+		r.vars[component] = v
+	}
+	v.Set(level)
+}
+
+// Unset はコンポーネントのレベル指定を外し、既定のレベルに従わせます。
+func (r *LevelRegistry) Unset(component string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.vars, component)
+}
+
+// Apply は spec の内容で既定とコンポーネントのレベルを置き換えます。
+// spec に無いコンポーネントの指定は外れます。
+func (r *LevelRegistry) Apply(spec LevelSpec) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.def.Set(spec.Default)
+	for component := range r.vars {
+		if _, ok := spec.Components[component]; !ok {
+			delete(r.vars, component)
+		}
+	}
+	for _, component := range slices.Sorted(maps.Keys(spec.Components)) {
+		r.setLocked(component, spec.Components[component])
+	}
+}
+
+// Level はコンポーネントに適用されるレベルを返します。指定が無ければ既定のレベルです。
+func (r *LevelRegistry) Level(component string) slog.Level {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if v, ok := r.vars[component]; ok {
+		return v.Level()
+	}
+	return r.def.Level()
+}
+
+// minLevel は、いずれかのコンポーネントで出力されうる最も低いレベルを返します。
+func (r *LevelRegistry) minLevel() slog.Level {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	level := r.def.Level()
+	for _, v := range r.vars {
+		level = min(level, v.Level())
+	}
+	return level
+}
+
+// NewLevelHandler は、LevelRegistry を参照してレコードを絞り込むハンドラーで base を包みます。
+//
+// コンポーネントは、呼び出し時の属性、context 属性、logger.With の属性の順に探し、
+// 最初に見つかったものを使います。グループの内外は区別しません。
+// 絞り込みはこのハンドラーが担うため、base のレベルは registry で下げうる最低レベル以下にしてください。
+func NewLevelHandler(base slog.Handler, registry *LevelRegistry) slog.Handler {
+	return &levelHandler{base: base, registry: registry}
+}
+
+// levelHandler はコンポーネントごとのレベルでレコードを絞り込む slog.Handler です。
+type levelHandler struct {
+	base     slog.Handler
+	registry *LevelRegistry
+
+	// component は logger.With で積まれたコンポーネントです。
+	component    string
+	hasComponent bool
+}
+
+// Enabled は、いずれかのコンポーネントで出力されうるレベルかを判定します。
+// コンポーネントは呼び出し時の属性で上書きされうるため、ここでは確定させず Handle に委ねます。
+func (h *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.registry.minLevel() && h.base.Enabled(ctx, level)
+}
+
+// Handle はコンポーネントを確定させ、そのレベル未満のレコードを捨てます。
+func (h *levelHandler) Handle(ctx context.Context, record slog.Record) error {
+	component, ok := h.component, h.hasComponent
+	if c, found := findString(attrsFrom(ctx), h.registry.key); found {
+		component, ok = c, true
+	}
+	// NewHandler で包むと context 属性はレコードの末尾に移るため、最初に見つかったものを使い、
+	// 呼び出し時の属性を優先します。
+	record.Attrs(func(a slog.Attr) bool {
+		if a.Key == h.registry.key {
+			component, ok = a.Value.String(), true
+			return false
+		}
+		return true
+	})
+
+	threshold := h.registry.Default().Level()
+	if ok {
+		threshold = h.registry.Level(component)
+	}
+	if record.Level < threshold {
+		return nil
+	}
+	return h.base.Handle(ctx, record)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	derived := *h
+	derived.base = h.base.WithAttrs(attrs)
+	if component, ok := findString(attrs, h.registry.key); ok {
+		derived.component, derived.hasComponent = component, true
+	}
+	return &derived
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	derived := *h
+	derived.base = h.base.WithGroup(name)
+	return &derived
+}
+
+// findString は key の属性の値を文字列で返します。複数あれば最後のものを使います。
+func findString(attrs []slog.Attr, key string) (string, bool) {
+	for i := len(attrs) - 1; i >= 0; i-- {
+		if attrs[i].Key == key {
+			return attrs[i].Value.String(), true
+		}
+	}
+	return "", false
+}
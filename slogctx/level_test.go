@@ -0,0 +1,154 @@
+package slogctx
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"testing"
+)
+
+func TestParseLevelSpec(t *testing.T) {
+	tests := []struct {
+		spec string
+		want LevelSpec
+	}{
+		{"", LevelSpec{Default: slog.LevelInfo, Components: map[string]slog.Level{}}},
+		{"debug", LevelSpec{Default: slog.LevelDebug, Components: map[string]slog.Level{}}},
+		{
+			"info, db=debug ,http=WARN",
+			LevelSpec{Default: slog.LevelInfo, Components: map[string]slog.Level{"db": slog.LevelDebug, "http": slog.LevelWarn}},
+		},
+		{
+			// 既定のレベルは省略でき、位置も問わないこと。
+			"db=INFO+2,error",
+			LevelSpec{Default: slog.LevelError, Components: map[string]slog.Level{"db": slog.LevelInfo + 2}},
+		},
+	}
+	for _, tt := range tests {
+		got, err := ParseLevelSpec(tt.spec)
+		if err != nil {
+			t.Errorf("ParseLevelSpec(%q) error = %v", tt.spec, err)
+			continue
+		}
+		if got.Default != tt.want.Default || !maps.Equal(got.Components, tt.want.Components) {
+			t.Errorf("ParseLevelSpec(%q) = %+v, want %+v", tt.spec, got, tt.want)
+		}
+	}
+
+	invalid := map[string]string{
+		"verbose":         "未知のレベル",
+		"db=loud":         "未知のコンポーネントレベル",
+		"info,warn":       "既定のレベルの重複",
+		"db=info,db=warn": "コンポーネントの重複",
+		"=debug":          "空のコンポーネント名",
+		"info+two":        "不正なオフセット",
+	}
+	for spec, reason := range invalid {
+		if _, err := ParseLevelSpec(spec); err == nil {
+			t.Errorf("ParseLevelSpec(%q) = nil error, want an error (%s)", spec, reason)
+		}
+	}
+}
+
+func TestLevelRegistry(t *testing.T) {
+	reg := NewLevelRegistry("")
+	spec, err := ParseLevelSpec("warn,db=debug,http=error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg.Apply(spec)
+
+	if got := reg.Level("db"); got != slog.LevelDebug {
+		t.Errorf("Level(db) = %v, want DEBUG", got)
+	}
+	if got := reg.Level("other"); got != slog.LevelWarn {
+		t.Errorf("Level(other) = %v, want WARN", got)
+	}
+
+	// 再適用で spec に無いコンポーネントの指定が外れること。
+	reg.Apply(LevelSpec{Default: slog.LevelInfo, Components: map[string]slog.Level{"db": slog.LevelError}})
+	if got := reg.Level("http"); got != slog.LevelInfo {
+		t.Errorf("再適用後の Level(http) = %v, want INFO", got)
+	}
+
+	reg.Unset("db")
+	if got := reg.Level("db"); got != slog.LevelInfo {
+		t.Errorf("Unset 後の Level(db) = %v, want INFO", got)
+	}
+}
+
+func TestLevelHandler(t *testing.T) {
+	reg := NewLevelRegistry("")
+	reg.Default().Set(slog.LevelWarn)
+	reg.Set("db", slog.LevelDebug)
+
+	rec := NewRecorder()
+	logger := slog.New(NewHandler(NewLevelHandler(rec, reg)))
+
+	logger.Info("no component")                               // 既定の WARN 未満で捨てられる
+	logger.With("component", "db").Debug("query")             // logger.With のコンポーネント
+	logger.Debug("call site", slog.String("component", "db")) // 呼び出し時の属性
+	logger.DebugContext(With(context.Background(), slog.String("component", "db")), "from context")
+	logger.With("component", "db").Debug("overridden", slog.String("component", "http")) // 呼び出し時の属性が優先
+
+	var got []string
+	for _, e := range rec.Entries() {
+		got = append(got, e.Message())
+	}
+	want := []string{"query", "call site", "from context"}
+	if len(got) != len(want) {
+		t.Fatalf("messages = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("messages = %v, want %v", got, want)
+			break
+		}
+	}
+
+	// 実行中のレベル変更が反映されること。
+	rec.Reset()
+	reg.Set("db", slog.LevelError)
+	logger.With("component", "db").Warn("now filtered")
+	if n := len(rec.Entries()); n != 0 {
+		t.Errorf("レベル変更後も出力された: %d 件", n)
+	}
+}
+
+// 呼び出し時のコンポーネントが context のものより優先されること。NewHandler で包むと
+// context 属性はレコードの末尾に移るため、直接使う場合と包む場合の両方を確かめる。
+func TestLevelHandlerCallSiteOverridesContext(t *testing.T) {
+	reg := NewLevelRegistry("")
+	reg.Set("db", slog.LevelDebug)
+	reg.Set("http", slog.LevelWarn)
+	ctx := With(context.Background(), slog.String("component", "http"))
+
+	for name, wrap := range map[string]func(slog.Handler) slog.Handler{
+		"直接":         func(h slog.Handler) slog.Handler { return h },
+		"NewHandler": func(h slog.Handler) slog.Handler { return NewHandler(h) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			rec := NewRecorder()
+			logger := slog.New(wrap(NewLevelHandler(rec, reg)))
+			logger.DebugContext(ctx, "call site", slog.String("component", "db"))
+			logger.InfoContext(ctx, "from context")
+			if got := rec.Entries(); len(got) != 1 || got[0].Message() != "call site" {
+				t.Errorf("entries = %v, want only %q", got, "call site")
+			}
+		})
+	}
+}
+
+func TestLevelHandlerEnabled(t *testing.T) {
+	reg := NewLevelRegistry("")
+	reg.Default().Set(slog.LevelWarn)
+	h := NewLevelHandler(NewRecorder(), reg)
+
+	if h.Enabled(context.Background(), slog.LevelInfo) {
+		t.Error("どのコンポーネントも INFO を出さないのに Enabled(INFO) = true")
+	}
+	reg.Set("db", slog.LevelDebug)
+	if !h.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("db が DEBUG を出すのに Enabled(DEBUG) = false")
+	}
+}
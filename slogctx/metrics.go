@@ -0,0 +1,186 @@
+package slogctx
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"expvar"
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+// MetricsOverflowMessage は、MaxSeries を超えた組み合わせをまとめて数えるときのメッセージです。
+// ExtractorPanicKey と同じく、通常のメッセージと衝突しない名前にしています。
+const MetricsOverflowMessage = "!OVERFLOW"
+
+// DefaultMaxSeries は MetricsOptions.MaxSeries の既定値です。
+const DefaultMaxSeries = 1000
+
+// MetricsOptions は NewMetrics の設定です。ゼロ値のままでも使えます。
+type MetricsOptions struct {
+	// Key は、レベルとメッセージに加えて数え分ける属性のキーです。例えば DefaultComponentKey を
+	// 指定すると、コンポーネントごとの ERROR の件数が分かります。空なら属性では数え分けません。
+	Key string
+
+	// MaxSeries は数え分ける組み合わせの上限です。0 以下なら DefaultMaxSeries です。
+	// メッセージに値を埋め込むコードがあっても際限なくメモリを使わないよう、上限を超えた分は
+	// レベルごとに MetricsOverflowMessage の組み合わせへまとめます。
+	MaxSeries int
+}
+
+// MetricKey は数え分ける単位です。
+type MetricKey struct {
+	Level slog.Level
+	// Value は MetricsOptions.Key の属性の値です。Key が空か、属性が無ければ空です。
+	Value   string
+	Message string
+}
+
+// MetricCount は 1 つの組み合わせの件数です。
+type MetricCount struct {
+	MetricKey
+	Count uint64
+}
+
+// Metrics は、出力したログの件数をレベル・属性・メッセージの組み合わせごとに数えます。
+// NewMetricsHandler で包んだハンドラーが数え、Snapshot で取り出します。並行に使えます。
+//
+// *Metrics は expvar.Var を満たすため、Publish するか expvar.Map に入れると
+// /debug/vars から件数を確認できます。
+type Metrics struct {
+	key       string
+	maxSeries int
+
+	mu     sync.Mutex
+	counts map[MetricKey]uint64
+}
+
+// NewMetrics は空の Metrics を作ります。
+func NewMetrics(opts MetricsOptions) *Metrics {
+	maxSeries := opts.MaxSeries
+	if maxSeries <= 0 {
+		maxSeries = DefaultMaxSeries
+	}
+	return &Metrics{key: opts.Key, maxSeries: maxSeries, counts: map[MetricKey]uint64{}}
+}
+
+// Snapshot は現在の件数を、レベルの高い順、同じレベルでは件数の多い順に返します。
+// 戻り値は複製で、以降の記録の影響を受けません。
+func (m *Metrics) Snapshot() []MetricCount {
+	m.mu.Lock()
+	counts := make([]MetricCount, 0, len(m.counts))
+	for key, n := range m.counts {
+		counts = append(counts, MetricCount{MetricKey: key, Count: n})
+	}
+	m.mu.Unlock()
+
+	slices.SortFunc(counts, func(a, b MetricCount) int {
+		return cmp.Or(
+			cmp.Compare(b.Level, a.Level),
+			cmp.Compare(b.Count, a.Count),
+			cmp.Compare(a.Value, b.Value),
+			cmp.Compare(a.Message, b.Message),
+		)
+	})
+	return counts
+}
+
+// Reset はすべての件数を破棄します。
+func (m *Metrics) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	clear(m.counts)
+}
+
+// String は Snapshot を JSON の配列で返します。expvar.Var を満たすためのメソッドです。
+func (m *Metrics) String() string {
+	type series struct {
+		Level   string `json:"level"`
+		Value   string `json:"value,omitempty"`
+		Message string `json:"msg"`
+		Count   uint64 `json:"count"`
+	}
+	snapshot := m.Snapshot()
+	out := make([]series, 0, len(snapshot))
+	for _, c := range snapshot {
+		out = append(out, series{Level: c.Level.String(), Value: c.Value, Message: c.Message, Count: c.Count})
+	}
+	b, err := json.Marshal(out)
+	if err != nil {
+		return "[]"
+	}
+	return string(b)
+}
+
+// Publish は name で expvar へ登録します。expvar.Publish と同じく、同じ名前で 2 回登録すると panic します。
+func (m *Metrics) Publish(name string) {
+	expvar.Publish(name, m)
+}
+
+func (m *Metrics) add(key MetricKey) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.counts[key]; !ok && len(m.counts) >= m.maxSeries {
+		key = MetricKey{Level: key.Level, Message: MetricsOverflowMessage}
+	}
+	m.counts[key]++
+}
+
+// NewMetricsHandler は、受け取ったレコードを metrics で数えてから base へ渡すハンドラーを返します。
+//
+// 数えるのは base が出力を有効にしているレベルのレコードで、base がエラーを返しても数えます。
+// 属性の値は呼び出し時の属性、context 属性、logger.With の属性の順に優先します。
+func NewMetricsHandler(base slog.Handler, metrics *Metrics) slog.Handler {
+	return &metricsHandler{base: base, metrics: metrics}
+}
+
+// metricsHandler はレコードを数える slog.Handler です。
+type metricsHandler struct {
+	base    slog.Handler
+	metrics *Metrics
+
+	// value は logger.With で積まれた、数え分ける属性の値です。
+	value string
+}
+
+func (h *metricsHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.base.Enabled(ctx, level)
+}
+
+func (h *metricsHandler) Handle(ctx context.Context, record slog.Record) error {
+	key := MetricKey{Level: record.Level, Message: record.Message}
+	if h.metrics.key != "" {
+		key.Value = h.value
+		if v, ok := findString(attrsFrom(ctx), h.metrics.key); ok {
+			key.Value = v
+		}
+		// NewHandler で包むと context 属性はレコードの末尾に移るため、最初に見つかったものを使います。
+		record.Attrs(func(a slog.Attr) bool {
+			if a.Key == h.metrics.key {
+				key.Value = a.Value.String()
+				return false
+			}
+			return true
+		})
+	}
+	h.metrics.add(key)
+	return h.base.Handle(ctx, record)
+}
+
+func (h *metricsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	derived := *h
+	derived.base = h.base.WithAttrs(attrs)
+	if h.metrics.key != "" {
+		if v, ok := findString(attrs, h.metrics.key); ok {
+			derived.value = v
+		}
+	}
+	return &derived
+}
+
+func (h *metricsHandler) WithGroup(name string) slog.Handler {
+	derived := *h
+	derived.base = h.base.WithGroup(name)
+	return &derived
+}
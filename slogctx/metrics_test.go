@@ -0,0 +1,131 @@
+package slogctx
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+)
+
+func TestMetricsCounts(t *testing.T) {
+	metrics := NewMetrics(MetricsOptions{Key: DefaultComponentKey})
+	base := slog.NewJSONHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelInfo})
+	logger := slog.New(NewMetricsHandler(base, metrics))
+
+	db := logger.With(DefaultComponentKey, "db")
+	db.Error("query failed")
+	db.Error("query failed")
+	db.Error("query failed", DefaultComponentKey, "cache") // 呼び出し時の属性が優先
+	db.ErrorContext(With(context.Background(), slog.String(DefaultComponentKey, "queue")), "query failed")
+	logger.Info("started")
+	logger.Debug("ignored") // base で無効なレベルは数えない
+
+	want := []MetricCount{
+		{MetricKey{slog.LevelError, "db", "query failed"}, 2},
+		{MetricKey{slog.LevelError, "cache", "query failed"}, 1},
+		{MetricKey{slog.LevelError, "queue", "query failed"}, 1},
+		{MetricKey{slog.LevelInfo, "", "started"}, 1},
+	}
+	got := metrics.Snapshot()
+	if len(got) != len(want) {
+		t.Fatalf("Snapshot() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Snapshot()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	metrics.Reset()
+	if got := metrics.Snapshot(); len(got) != 0 {
+		t.Errorf("Reset 後の Snapshot() = %v, want 空", got)
+	}
+}
+
+// NewHandler で包んでも、呼び出し時の属性が context 属性より優先されること。
+func TestMetricsCallSiteOverridesContextUnderNewHandler(t *testing.T) {
+	metrics := NewMetrics(MetricsOptions{Key: DefaultComponentKey})
+	logger := slog.New(NewHandler(NewMetricsHandler(NewRecorder(), metrics)))
+	ctx := With(context.Background(), slog.String(DefaultComponentKey, "http"))
+
+	logger.ErrorContext(ctx, "failed", DefaultComponentKey, "db")
+	logger.ErrorContext(ctx, "failed")
+
+	want := []MetricCount{
+		{MetricKey{slog.LevelError, "db", "failed"}, 1},
+		{MetricKey{slog.LevelError, "http", "failed"}, 1},
+	}
+	got := metrics.Snapshot()
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Snapshot() = %v, want %v", got, want)
+	}
+}
+
+func TestMetricsOverflow(t *testing.T) {
+	metrics := NewMetrics(MetricsOptions{MaxSeries: 2})
+	logger := slog.New(NewMetricsHandler(NewRecorder(), metrics))
+
+	logger.Warn("a")
+	logger.Warn("b")
+	logger.Warn("c")
+	logger.Warn("d")
+	logger.Warn("a")
+
+	want := []MetricCount{
+		{MetricKey{slog.LevelWarn, "", MetricsOverflowMessage}, 2},
+		{MetricKey{slog.LevelWarn, "", "a"}, 2},
+		{MetricKey{slog.LevelWarn, "", "b"}, 1},
+	}
+	got := metrics.Snapshot()
+	if len(got) != len(want) {
+		t.Fatalf("Snapshot() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Snapshot()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestMetricsExpvar(t *testing.T) {
+	metrics := NewMetrics(MetricsOptions{Key: DefaultComponentKey})
+	metrics.Publish("slogctx_test_metrics")
+	slog.New(NewMetricsHandler(NewRecorder(), metrics)).Error("boom", DefaultComponentKey, "db")
+
+	v := expvar.Get("slogctx_test_metrics")
+	if v == nil {
+		t.Fatal("expvar に登録されていない")
+	}
+	var got []map[string]any
+	if err := json.Unmarshal([]byte(v.String()), &got); err != nil {
+		t.Fatalf("String() が JSON ではない: %v", err)
+	}
+	if len(got) != 1 || got[0]["level"] != "ERROR" || got[0]["value"] != "db" || got[0]["msg"] != "boom" || got[0]["count"] != 1.0 {
+		t.Errorf("String() = %s", v.String())
+	}
+}
+
+func TestMetricsConcurrent(t *testing.T) {
+	metrics := NewMetrics(MetricsOptions{})
+	logger := slog.New(NewMetricsHandler(NewRecorder(), metrics))
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				logger.Info("tick")
+				_ = metrics.Snapshot()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := metrics.Snapshot(); len(got) != 1 || got[0].Count != 800 {
+		t.Errorf("Snapshot() = %v, want tick が 800 件", got)
+	}
+}
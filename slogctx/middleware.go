@@ -0,0 +1,273 @@
+package slogctx
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"time"
+)
+
+// 属性のキーと、リクエスト ID を受け渡すヘッダーの既定値です。
+const (
+	DefaultRequestIDHeader = "X-Request-Id"
+	DefaultRequestIDKey    = "request_id"
+)
+
+// maxRequestIDLength は、上流から受け取るリクエスト ID の最大長です。
+// これを超える値や制御文字を含む値は、ログの汚染を避けるため捨てて採番し直します。
+const maxRequestIDLength = 128
+
+// MiddlewareOptions は Middleware の設定です。ゼロ値のままでも使えます。
+type MiddlewareOptions struct {
+	// Logger はアクセスログの出力先です。nil なら slog.Default() を使います。
+	Logger *slog.Logger
+
+	// RequestIDHeader はリクエスト ID を受け取り、レスポンスへ書き戻すヘッダー名です。
+	// 空なら DefaultRequestIDHeader を使います。
+	RequestIDHeader string
+
+	// RequestIDKey はリクエスト ID の属性キーです。空なら DefaultRequestIDKey を使います。
+	RequestIDKey string
+
+	// NewRequestID は、上流からリクエスト ID が渡されなかったときの採番関数です。
+	// nil なら乱数から 16 桁の 16 進数を生成します。
+	NewRequestID func() string
+
+	// TrustedProxies は、X-Forwarded-For を信頼するプロキシのアドレス範囲です。
+	// 空なら X-Forwarded-For を無視し、接続元のアドレスをクライアント IP とみなします。
+	TrustedProxies []netip.Prefix
+
+	// Trace が nil でなければ、トレースヘッダーを WithTrace でこのキーへ積みます。
+	// ヘッダーが無ければ新しいトレースを採番します。
+	Trace *TraceKeys
+
+	// Attrs は、既定の属性に加えて context へ積む属性を取り出します。
+	Attrs func(r *http.Request) []slog.Attr
+
+	// DisableAccessLog が true なら、レスポンス後のアクセスログを出力しません。
+	DisableAccessLog bool
+}
+
+// Middleware は、リクエスト単位の属性を With で context へ積む net/http のミドルウェアを返します。
+//
+// 積む属性はリクエスト ID、メソッド、パス、クライアント IP と、MiddlewareOptions.Attrs が返すものです。
+// ハンドラーが返ったあと、ステータスとレイテンシを添えたアクセスログを同じ context で出力します。
+// レベルは 5xx が Error、4xx が Warn、それ以外が Info です。
+func Middleware(opts MiddlewareOptions) func(http.Handler) http.Handler {
+	header := cmp.Or(opts.RequestIDHeader, DefaultRequestIDHeader)
+	key := cmp.Or(opts.RequestIDKey, DefaultRequestIDKey)
+	newID := opts.NewRequestID
+	if newID == nil {
+		newID = newRequestID
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			requestID := r.Header.Get(header)
+			if !isSafeRequestID(requestID) {
+				requestID = newID()
+			}
+			w.Header().Set(header, requestID)
+
+			attrs := []slog.Attr{
+				slog.String(key, requestID),
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.String("remote_ip", ClientIP(r, opts.TrustedProxies)),
+			}
+			if opts.Attrs != nil {
+				attrs = append(attrs, opts.Attrs(r)...)
+			}
+			ctx := With(r.Context(), attrs...)
+			if opts.Trace != nil {
+				ctx = withRequestTrace(ctx, r.Header, *opts.Trace)
+			}
+
+			sw := &statusWriter{ResponseWriter: w}
+			next.ServeHTTP(sw, r.WithContext(ctx))
+
+			if opts.DisableAccessLog {
+				return
+			}
+			logger := opts.Logger
+			if logger == nil {
+				logger = slog.Default()
+			}
+			status := sw.Status()
+			logger.LogAttrs(ctx, accessLogLevel(status), "http request",
+				slog.Int("status", status),
+				slog.Duration("latency", time.Since(start)),
+				slog.Int64("bytes", sw.written),
+			)
+		})
+	}
+}
+
+func withRequestTrace(ctx context.Context, h http.Header, keys TraceKeys) context.Context {
+	tc, ok := TraceFromHeader(h)
+	if !ok {
+		var err error
+		if tc, err = NewTraceContext(); err != nil {
+			return ctx
+		}
+	}
+	return WithTrace(ctx, tc, keys)
+}
+
+func accessLogLevel(status int) slog.Level {
+	switch {
+	case status >= 500:
+		return slog.LevelError
+	case status >= 400:
+		return slog.LevelWarn
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// ClientIP はリクエストのクライアント IP を返します。
+//
+// 接続元が trusted に含まれる場合に限り、X-Forwarded-For を右から辿り、
+// 信頼できるプロキシではない最初のアドレスをクライアントとみなします。左端は
+// クライアントが自由に書けるため、先頭の値をそのまま信じることはしません。
+func ClientIP(r *http.Request, trusted []netip.Prefix) string {
+	remote := remoteAddr(r.RemoteAddr)
+	if len(trusted) == 0 || !isTrusted(remote, trusted) {
+		return remote
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		if !isTrusted(hop, trusted) {
+			return hop
+		}
+		remote = hop
+	}
+	return remote
+}
+
+// remoteAddr は "host:port" 形式の RemoteAddr からホスト部分を取り出します。
+func remoteAddr(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+func isTrusted(ip string, trusted []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, p := range trusted {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSafeRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if r <= ' ' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	buf := make([]byte, 8)
+	// crypto/rand.Read は失敗しない（失敗時はプロセスを停止する）ため、エラーは見ません。
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// statusWriter は、書き込まれたステータスとバイト数を記録する http.ResponseWriter です。
+//
+// http.Flusher と http.Hijacker は委譲先が対応していればそのまま使えます。対応していない場合、
+// http.ResponseController の Flush と Hijack は http.ErrNotSupported を返します。それ以外の
+// 拡張インターフェースは Unwrap を通じて http.ResponseController から辿れます。
+type statusWriter struct {
+	http.ResponseWriter
+	status  int
+	written int64
+}
+
+// Status は書き込まれたステータスを返します。明示的に書かれていなければ 200 です。
+func (w *statusWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	// 1xx は最終的なステータスではないため記録しません。
+	if w.status == 0 && status >= 200 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.written += int64(n)
+	return n, err
+}
+
+func (w *statusWriter) Flush() {
+	_ = w.FlushError()
+}
+
+// FlushError は委譲先をフラッシュします。委譲先が対応していなければ http.ErrNotSupported を返し、
+// http.ResponseController.Flush がフラッシュできなかったことを報告できるようにします。
+func (w *statusWriter) FlushError() error {
+	if err := http.NewResponseController(w.ResponseWriter).Flush(); err != nil {
+		return err
+	}
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return nil
+}
+
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	conn, rw, err := h.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	// 乗っ取られた接続は HTTP のステータスを持たないため、101 として扱います。
+	if w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, nil
+}
+
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
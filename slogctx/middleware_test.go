@@ -0,0 +1,266 @@
+package slogctx
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestMiddlewareSeedsContextAndLogsAccess(t *testing.T) {
+	rec := NewRecorder()
+	logger := slog.New(rec)
+
+	var handlerAttrs []slog.Attr
+	h := Middleware(MiddlewareOptions{
+		Logger:       logger,
+		NewRequestID: func() string { return "generated" },
+		Attrs: func(r *http.Request) []slog.Attr {
+			return []slog.Attr{slog.String("tenant", r.Header.Get("X-Tenant"))}
+		},
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerAttrs = Attrs(r.Context())
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("missing"))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/jobs/1?x=y", nil)
+	req.RemoteAddr = "192.0.2.10:5555"
+	req.Header.Set("X-Tenant", "acme")
+	res := httptest.NewRecorder()
+	h.ServeHTTP(res, req)
+
+	if got := res.Header().Get(DefaultRequestIDHeader); got != "generated" {
+		t.Errorf("レスポンスの %s = %q, want generated", DefaultRequestIDHeader, got)
+	}
+	if len(handlerAttrs) != 5 {
+		t.Errorf("ハンドラーから見える属性 = %v, want 5 件", handlerAttrs)
+	}
+
+	rec.RequireLogged(t, slog.LevelWarn, "http request",
+		slog.String(DefaultRequestIDKey, "generated"),
+		slog.String("method", http.MethodGet),
+		slog.String("path", "/jobs/1"),
+		slog.String("remote_ip", "192.0.2.10"),
+		slog.String("tenant", "acme"),
+		slog.Int("status", http.StatusNotFound),
+		slog.Int64("bytes", int64(len("missing"))),
+	)
+}
+
+func TestMiddlewareRequestID(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{"上流の ID を引き継ぐ", "req-123", "req-123"},
+		{"空なら採番する", "", "generated"},
+		{"制御文字を含む値は捨てる", "bad\nid", "generated"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := NewRecorder()
+			h := Middleware(MiddlewareOptions{
+				Logger:          slog.New(rec),
+				RequestIDHeader: "X-Correlation-Id",
+				RequestIDKey:    "correlation_id",
+				NewRequestID:    func() string { return "generated" },
+			})(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("X-Correlation-Id", tt.header)
+			}
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			rec.RequireLogged(t, slog.LevelInfo, "http request",
+				slog.String("correlation_id", tt.want),
+				slog.Int("status", http.StatusOK),
+			)
+		})
+	}
+}
+
+func TestMiddlewareTrace(t *testing.T) {
+	rec := NewRecorder()
+	keys := CloudTraceKeys("my-project")
+	h := Middleware(MiddlewareOptions{Logger: slog.New(rec), Trace: &keys})(
+		http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(HeaderTraceparent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	rec.RequireLogged(t, slog.LevelInfo, "http request",
+		slog.String(CloudTraceKey, "projects/my-project/traces/4bf92f3577b34da6a3ce929d0e0e4736"),
+	)
+
+	// ヘッダーが無ければ新しいトレースを採番すること。
+	rec.Reset()
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	e := rec.RequireLogged(t, slog.LevelInfo, "http request")
+	if _, ok := e.AttrValue(CloudSpanIDKey); !ok {
+		t.Errorf("採番したトレースのスパン ID が積まれていない: %v", e.Attrs)
+	}
+}
+
+func TestMiddlewareDisableAccessLog(t *testing.T) {
+	rec := NewRecorder()
+	h := Middleware(MiddlewareOptions{Logger: slog.New(rec), DisableAccessLog: true})(
+		http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}),
+	)
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if got := rec.Entries(); len(got) != 0 {
+		t.Errorf("アクセスログが出力された: %v", got)
+	}
+}
+
+func TestAccessLogLevel(t *testing.T) {
+	tests := map[int]slog.Level{
+		http.StatusOK:                  slog.LevelInfo,
+		http.StatusFound:               slog.LevelInfo,
+		http.StatusBadRequest:          slog.LevelWarn,
+		http.StatusInternalServerError: slog.LevelError,
+	}
+	for status, want := range tests {
+		if got := accessLogLevel(status); got != want {
+			t.Errorf("accessLogLevel(%d) = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+
+	tests := []struct {
+		name    string
+		remote  string
+		xff     string
+		trusted []netip.Prefix
+		want    string
+	}{
+		{"信頼設定なしでは X-Forwarded-For を無視する", "10.0.0.1:80", "203.0.113.5", nil, "10.0.0.1"},
+		{"信頼していない接続元からの X-Forwarded-For は無視する", "198.51.100.7:80", "203.0.113.5", trusted, "198.51.100.7"},
+		{"信頼できるプロキシ経由なら右から辿る", "10.0.0.1:80", "203.0.113.5, 10.1.1.1", trusted, "203.0.113.5"},
+		{"左端の偽装は採用しない", "10.0.0.1:80", "1.1.1.1, 203.0.113.5", trusted, "203.0.113.5"},
+		{"すべて信頼できるなら最左を採用する", "10.0.0.1:80", "10.2.2.2", trusted, "10.2.2.2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remote
+			if tt.xff != "" {
+				req.Header.Set("X-Forwarded-For", tt.xff)
+			}
+			if got := ClientIP(req, tt.trusted); got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// hijackRecorder は Hijack に対応した ResponseWriter です。
+// err を設定すると Hijack はそのエラーで失敗します。
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+	err      error
+}
+
+func (h *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h.err != nil {
+		return nil, nil, h.err
+	}
+	h.hijacked = true
+	return nil, nil, nil
+}
+
+// plainWriter は Flush にも Hijack にも対応していない ResponseWriter です。
+type plainWriter struct {
+	header http.Header
+}
+
+func (p *plainWriter) Header() http.Header         { return p.header }
+func (p *plainWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (p *plainWriter) WriteHeader(int)             {}
+
+func TestStatusWriterPreservesInterfaces(t *testing.T) {
+	res := httptest.NewRecorder()
+	sw := &statusWriter{ResponseWriter: res}
+
+	http.ResponseWriter(sw).(http.Flusher).Flush()
+	if !res.Flushed {
+		t.Error("Flush が委譲されていない")
+	}
+	if _, _, err := http.ResponseWriter(sw).(http.Hijacker).Hijack(); !errors.Is(err, http.ErrNotSupported) {
+		t.Errorf("非対応の Hijack() error = %v, want http.ErrNotSupported", err)
+	}
+
+	hr := &hijackRecorder{ResponseRecorder: httptest.NewRecorder()}
+	sw = &statusWriter{ResponseWriter: hr}
+	if _, _, err := sw.Hijack(); err != nil || !hr.hijacked {
+		t.Errorf("Hijack() が委譲されていない: err = %v", err)
+	}
+	if sw.Status() != http.StatusSwitchingProtocols {
+		t.Errorf("Hijack 後の Status() = %d, want 101", sw.Status())
+	}
+
+	// 失敗した Hijack を 101 として記録しないこと。
+	errHijack := errors.New("hijack failed")
+	sw = &statusWriter{ResponseWriter: &hijackRecorder{ResponseRecorder: httptest.NewRecorder(), err: errHijack}}
+	if _, _, err := sw.Hijack(); !errors.Is(err, errHijack) {
+		t.Errorf("Hijack() error = %v, want %v", err, errHijack)
+	}
+	if sw.status != 0 {
+		t.Errorf("失敗した Hijack 後の status = %d, want 0", sw.status)
+	}
+
+	// http.ResponseController から委譲先を辿れること。
+	if err := http.NewResponseController(&statusWriter{ResponseWriter: res}).Flush(); err != nil {
+		t.Errorf("ResponseController.Flush() error = %v", err)
+	}
+
+	// フラッシュできない委譲先では、フラッシュしたことにしないこと。
+	sw = &statusWriter{ResponseWriter: &plainWriter{header: http.Header{}}}
+	if err := http.NewResponseController(sw).Flush(); !errors.Is(err, http.ErrNotSupported) {
+		t.Errorf("非対応の ResponseController.Flush() error = %v, want http.ErrNotSupported", err)
+	}
+	if sw.status != 0 {
+		t.Errorf("失敗した Flush 後の status = %d, want 0", sw.status)
+	}
+}
+
+// 実サーバー経由でもステータスと context 属性が届くこと。
+func TestMiddlewareWithServer(t *testing.T) {
+	rec := NewRecorder()
+	logger := slog.New(NewHandler(rec))
+	srv := httptest.NewServer(Middleware(MiddlewareOptions{Logger: logger})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			logger.InfoContext(r.Context(), "handling")
+			w.WriteHeader(http.StatusInternalServerError)
+		}),
+	))
+	defer srv.Close()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, srv.URL+"/render", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = res.Body.Close()
+
+	id := res.Header.Get(DefaultRequestIDHeader)
+	rec.RequireLogged(t, slog.LevelInfo, "handling", slog.String(DefaultRequestIDKey, id), slog.String("path", "/render"))
+	rec.RequireLogged(t, slog.LevelError, "http request", slog.String(DefaultRequestIDKey, id), slog.Int("status", 500))
+}
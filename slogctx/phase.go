@@ -0,0 +1,108 @@
+package slogctx
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// DefaultPhaseKey は、Start が context へ積むフェーズの属性キーの既定値です。
+const DefaultPhaseKey = "phase"
+
+// Phases はフェーズの開始・終了ログの出力方法です。ゼロ値のままでも使えます。
+type Phases struct {
+	// Logger はログの出力先です。nil なら slog.Default() を使います。
+	Logger *slog.Logger
+
+	// Now は所要時間の計測に使う時計です。nil なら time.Now を使います。テストで差し替えます。
+	Now func() time.Time
+
+	// Key はフェーズの属性キーです。空なら DefaultPhaseKey を使います。
+	Key string
+}
+
+// phaseKey は、実行中のフェーズのパスを context に保持するためのキーです。
+type phaseKey struct{}
+
+// Start は既定の Phases でフェーズを開始します。Phases.Start を参照してください。
+func Start(ctx context.Context, name string, attrs ...slog.Attr) (context.Context, func(errp *error)) {
+	return Phases{}.Start(ctx, name, attrs...)
+}
+
+// Start はフェーズの開始を記録し、フェーズの属性を積んだ context と終了関数を返します。
+//
+//	ctx, end := slogctx.Start(ctx, "transcode", slog.String("codec", "h264"))
+//	defer end(&err)
+//
+// 終了関数には名前付き戻り値のエラーへのポインタを渡します。所要時間と成否を記録し、
+// 失敗なら Error、context のキャンセルによる中断なら Warn、成功なら Info で出力します。
+// nil を渡すと成功とみなします。2 回目以降の呼び出しは何もしません。
+//
+// フェーズの中で開始したフェーズは、親からのパスを "job/transcode" のようにスラッシュで
+// つないだ値を属性にします。context に積むフェーズの属性は常に 1 つで、入れ子にしても重複しません。
+// attrs は開始と終了のログにだけ添え、context には積みません。
+func (p Phases) Start(ctx context.Context, name string, attrs ...slog.Attr) (context.Context, func(errp *error)) {
+	logger := p.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	now := p.Now
+	if now == nil {
+		now = time.Now
+	}
+	key := p.Key
+	if key == "" {
+		key = DefaultPhaseKey
+	}
+
+	path := name
+	if parent, ok := ctx.Value(phaseKey{}).(string); ok && parent != "" {
+		path = parent + "/" + name
+	}
+	ctx = context.WithValue(ctx, phaseKey{}, path)
+	ctx = withReplaced(ctx, slog.String(key, path))
+
+	start := now()
+	logger.LogAttrs(ctx, slog.LevelInfo, "phase started", attrs...)
+
+	var once sync.Once
+	return ctx, func(errp *error) {
+		once.Do(func() {
+			var err error
+			if errp != nil {
+				err = *errp
+			}
+
+			level, outcome := slog.LevelInfo, "success"
+			switch {
+			case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+				level, outcome = slog.LevelWarn, "canceled"
+			case err != nil:
+				level, outcome = slog.LevelError, "failure"
+			}
+
+			end := make([]slog.Attr, 0, len(attrs)+3)
+			end = append(end, attrs...)
+			end = append(end, slog.Duration("duration", now().Sub(start)), slog.String("outcome", outcome))
+			if err != nil {
+				end = append(end, slog.Any("error", err))
+			}
+			logger.LogAttrs(ctx, level, "phase finished", end...)
+		})
+	}
+}
+
+// withReplaced は、同じキーの属性を取り除いたうえで attr を context へ積みます。
+func withReplaced(ctx context.Context, attr slog.Attr) context.Context {
+	existing := attrsFrom(ctx)
+	merged := make([]slog.Attr, 0, len(existing)+1)
+	for _, a := range existing {
+		if a.Key != attr.Key {
+			merged = append(merged, a)
+		}
+	}
+	merged = append(merged, attr)
+	return context.WithValue(ctx, contextKey{}, merged)
+}
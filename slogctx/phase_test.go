@@ -0,0 +1,116 @@
+package slogctx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// fakeClock は呼ばれるたびに step ずつ進む時計です。
+type fakeClock struct {
+	now  time.Time
+	step time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	t := c.now
+	c.now = c.now.Add(c.step)
+	return t
+}
+
+func newTestPhases(rec *Recorder) Phases {
+	clock := &fakeClock{now: time.Date(2026, time.July, 25, 0, 0, 0, 0, time.UTC), step: 2 * time.Second}
+	return Phases{Logger: slog.New(rec), Now: clock.Now}
+}
+
+func TestPhaseSuccess(t *testing.T) {
+	rec := NewRecorder()
+	p := newTestPhases(rec)
+
+	ctx, end := p.Start(context.Background(), "transcode", slog.String("codec", "h264"))
+	if attrs := Attrs(ctx); len(attrs) != 1 || attrs[0].Value.String() != "transcode" {
+		t.Errorf("Attrs() = %v, want phase=transcode", attrs)
+	}
+	var err error
+	end(&err)
+	end(&err) // 2 回目は記録しない
+
+	rec.RequireLogged(t, slog.LevelInfo, "phase started", slog.String("phase", "transcode"), slog.String("codec", "h264"))
+	rec.RequireLogged(t, slog.LevelInfo, "phase finished",
+		slog.String("phase", "transcode"),
+		slog.String("codec", "h264"),
+		slog.Duration("duration", 2*time.Second),
+		slog.String("outcome", "success"),
+	)
+	if n := len(rec.Entries()); n != 2 {
+		t.Errorf("entries = %d, want 2", n)
+	}
+}
+
+func TestPhaseOutcome(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantLevel   slog.Level
+		wantOutcome string
+	}{
+		{"失敗は Error", errors.New("boom"), slog.LevelError, "failure"},
+		{"キャンセルは Warn", fmt.Errorf("wrap: %w", context.Canceled), slog.LevelWarn, "canceled"},
+		{"タイムアウトは Warn", context.DeadlineExceeded, slog.LevelWarn, "canceled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := NewRecorder()
+			_, end := newTestPhases(rec).Start(context.Background(), "upload")
+			err := tt.err
+			end(&err)
+
+			e := rec.RequireLogged(t, tt.wantLevel, "phase finished", slog.String("outcome", tt.wantOutcome))
+			if v, ok := e.AttrValue("error"); !ok || !errors.Is(v.Any().(error), tt.err) {
+				t.Errorf("error 属性 = %v, want %v", v, tt.err)
+			}
+		})
+	}
+
+	// nil ポインタは成功とみなすこと。
+	rec := NewRecorder()
+	_, end := newTestPhases(rec).Start(context.Background(), "upload")
+	end(nil)
+	rec.RequireLogged(t, slog.LevelInfo, "phase finished", slog.String("outcome", "success"))
+}
+
+func TestPhaseNested(t *testing.T) {
+	rec := NewRecorder()
+	p := newTestPhases(rec)
+
+	ctx, endJob := p.Start(With(context.Background(), slog.String("job_id", "job-1")), "job")
+	inner, endEncode := p.Start(ctx, "encode")
+
+	// フェーズの属性は入れ子にしても 1 つだけで、他の属性は保たれること。
+	attrs := Attrs(inner)
+	if len(attrs) != 2 || attrs[0].Key != "job_id" || attrs[1].Value.String() != "job/encode" {
+		t.Errorf("Attrs() = %v, want [job_id phase=job/encode]", attrs)
+	}
+
+	endEncode(nil)
+	endJob(nil)
+
+	rec.RequireLogged(t, slog.LevelInfo, "phase finished", slog.String("phase", "job/encode"), slog.String("job_id", "job-1"))
+	rec.RequireLogged(t, slog.LevelInfo, "phase finished", slog.String("phase", "job"))
+}
+
+func TestPhaseCustomKey(t *testing.T) {
+	rec := NewRecorder()
+	p := newTestPhases(rec)
+	p.Key = "step"
+
+	ctx, end := p.Start(context.Background(), "collect")
+	end(nil)
+
+	if attrs := Attrs(ctx); len(attrs) != 1 || attrs[0].Key != "step" {
+		t.Errorf("Attrs() = %v, want [step]", attrs)
+	}
+}
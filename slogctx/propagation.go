@@ -0,0 +1,250 @@
+package slogctx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// ErrUnsafeAttr は、伝播できないキーまたは値の属性を拒否したことを表します。
+// errors.Is で判定できます。
+var ErrUnsafeAttr = errors.New("attribute cannot be propagated")
+
+// 伝播に使うマップのキーと大きさの既定値です。
+//
+// 上限は Pub/Sub の属性（キー 256 バイト、値 1024 バイト）と、一般的なプロキシが受け付ける
+// ヘッダーの大きさに収まるよう控えめにしています。
+const (
+	DefaultPropagationPrefix   = "x-slogctx-"
+	DefaultMaxPropagationValue = 256
+	DefaultMaxPropagationTotal = 4096
+	maxPropagationKeyLength    = 64
+)
+
+// PropagationOptions は EncodeAttrs / DecodeAttrs の設定です。送る側と受ける側で揃えてください。
+type PropagationOptions struct {
+	// Allow は伝播する属性のキーです。ここに無い属性は送らず、受け取っても復元しません。
+	// キーは英数字・アンダースコア・ハイフン・ドットだけで、64 文字以内にしてください。
+	Allow []string
+
+	// Prefix はマップのキーの接頭辞です。空なら DefaultPropagationPrefix を使います。
+	//
+	// マップのキーは、接頭辞に属性のキーを小文字にし、アンダースコアとドットをハイフンにしたものを
+	// 続けたものです。"job_id" は "x-slogctx-job-id" になります。nginx などのプロキシは既定で
+	// アンダースコアやドットを含むヘッダーを捨てるため、英小文字・数字・ハイフンだけにしています。
+	// 受け取る側は Allow のキーから同じ規則でマップのキーを求めて復元するため、"job_id" と "job-id"
+	// のように同じマップのキーになるキーを Allow に並べると区別できず、どちらも伝播しません。
+	Prefix string
+
+	// MaxValueLen は 1 つの値の最大バイト数です。0 以下なら DefaultMaxPropagationValue です。
+	MaxValueLen int
+
+	// MaxTotalLen はキーと値を合わせた全体の最大バイト数です。0 以下なら DefaultMaxPropagationTotal です。
+	MaxTotalLen int
+}
+
+func (o PropagationOptions) prefix() string {
+	if o.Prefix == "" {
+		return DefaultPropagationPrefix
+	}
+	return strings.ToLower(o.Prefix)
+}
+
+// carrierName は key を伝播するマップのキーを返します。
+func (o PropagationOptions) carrierName(key string) string {
+	return o.prefix() + strings.Map(func(r rune) rune {
+		if r == '_' || r == '.' {
+			return '-'
+		}
+		return r
+	}, strings.ToLower(key))
+}
+
+// ambiguousKeys は、Allow のうち別のキーと同じマップのキーになるものを返します。
+func (o PropagationOptions) ambiguousKeys() map[string]bool {
+	keys := map[string][]string{}
+	for _, key := range o.Allow {
+		name := o.carrierName(key)
+		if !slices.Contains(keys[name], key) {
+			keys[name] = append(keys[name], key)
+		}
+	}
+	ambiguous := map[string]bool{}
+	for _, group := range keys {
+		if len(group) > 1 {
+			for _, key := range group {
+				ambiguous[key] = true
+			}
+		}
+	}
+	return ambiguous
+}
+
+func (o PropagationOptions) maxValueLen() int {
+	if o.MaxValueLen <= 0 {
+		return DefaultMaxPropagationValue
+	}
+	return o.MaxValueLen
+}
+
+func (o PropagationOptions) maxTotalLen() int {
+	if o.MaxTotalLen <= 0 {
+		return DefaultMaxPropagationTotal
+	}
+	return o.MaxTotalLen
+}
+
+// EncodeAttrs は、ctx に With で積んだ属性のうち Allow に含まれるものを文字列のマップへ書き出します。
+//
+// マップはそのまま HTTP ヘッダー、Pub/Sub のメッセージ属性、Cloud Tasks のヘッダーに使えます。
+// API サービスからキュー経由でワーカーへジョブを渡すとき、受け取った側で DecodeAttrs を呼べば
+// ジョブ ID などの相関用の属性を引き継げます。値は文字列として送ります。
+//
+// 同じキーが複数積まれていれば最後のものを使います。キーや値が安全でない属性、上限を超える属性は
+// 書き出さず、ErrUnsafeAttr をラップしたエラーにまとめて返します。その場合も、書き出せた分のマップは返します。
+func EncodeAttrs(ctx context.Context, opts PropagationOptions) (map[string]string, error) {
+	allowed := make(map[string]struct{}, len(opts.Allow))
+	for _, key := range opts.Allow {
+		allowed[key] = struct{}{}
+	}
+
+	latest := map[string]slog.Value{}
+	var order []string
+	for _, a := range attrsFrom(ctx) {
+		if _, ok := allowed[a.Key]; !ok {
+			continue
+		}
+		if _, ok := latest[a.Key]; !ok {
+			order = append(order, a.Key)
+		}
+		latest[a.Key] = a.Value.Resolve()
+	}
+
+	ambiguous := opts.ambiguousKeys()
+	carrier := make(map[string]string, len(order))
+	total := 0
+	var errs []error
+	for _, key := range order {
+		if ambiguous[key] {
+			errs = append(errs, fmt.Errorf("%w: %q has the same carrier name as another allowed key", ErrUnsafeAttr, key))
+			continue
+		}
+		value := latest[key]
+		if value.Kind() == slog.KindGroup {
+			errs = append(errs, fmt.Errorf("%w: %q is a group", ErrUnsafeAttr, key))
+			continue
+		}
+		encoded := value.String()
+		if err := checkPropagated(key, encoded, opts); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		name := opts.carrierName(key)
+		if total+len(name)+len(encoded) > opts.maxTotalLen() {
+			errs = append(errs, fmt.Errorf("%w: %q exceeds total size limit %d", ErrUnsafeAttr, key, opts.maxTotalLen()))
+			continue
+		}
+		total += len(name) + len(encoded)
+		carrier[name] = encoded
+	}
+	return carrier, errors.Join(errs...)
+}
+
+// DecodeAttrs は、EncodeAttrs が書き出したマップから Allow に含まれる属性を復元し、With で ctx へ積みます。
+//
+// キーの大文字小文字は区別しません。Allow に無いキーや接頭辞の付かないキーは、他の用途の
+// ヘッダーや属性とみなして黙って無視します。値が安全でないもの、上限を超えるものは積まず、
+// ErrUnsafeAttr をラップしたエラーにまとめて返します。その場合も、復元できた分は ctx へ積みます。
+func DecodeAttrs(ctx context.Context, carrier map[string]string, opts PropagationOptions) (context.Context, error) {
+	ambiguous := opts.ambiguousKeys()
+	var attrs []slog.Attr
+	var errs []error
+	total := 0
+	// 受け取った側の並びはマップの順序に左右されないよう、Allow の順に復元します。
+	for _, key := range opts.Allow {
+		name := opts.carrierName(key)
+		value, ok := lookupFold(carrier, name)
+		if !ok {
+			continue
+		}
+		if ambiguous[key] {
+			errs = append(errs, fmt.Errorf("%w: %q has the same carrier name as another allowed key", ErrUnsafeAttr, key))
+			continue
+		}
+		if err := checkPropagated(key, value, opts); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if total+len(name)+len(value) > opts.maxTotalLen() {
+			errs = append(errs, fmt.Errorf("%w: %q exceeds total size limit %d", ErrUnsafeAttr, key, opts.maxTotalLen()))
+			continue
+		}
+		total += len(name) + len(value)
+		attrs = append(attrs, slog.String(key, value))
+	}
+	return With(ctx, attrs...), errors.Join(errs...)
+}
+
+// DecodeHeader は HTTP ヘッダーから属性を復元する DecodeAttrs です。
+// 同じヘッダーが複数あれば最初の値を使います。
+func DecodeHeader(ctx context.Context, h http.Header, opts PropagationOptions) (context.Context, error) {
+	carrier := make(map[string]string, len(h))
+	for name, values := range h {
+		if len(values) > 0 {
+			carrier[name] = values[0]
+		}
+	}
+	return DecodeAttrs(ctx, carrier, opts)
+}
+
+// lookupFold は、大文字小文字を区別せずに name の値を探します。
+func lookupFold(carrier map[string]string, name string) (string, bool) {
+	if v, ok := carrier[name]; ok {
+		return v, true
+	}
+	for k, v := range carrier {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// checkPropagated は、キーと値がヘッダーやメッセージ属性に載せても安全かを検査します。
+//
+// 値は印字可能な ASCII に限ります。改行などの制御文字はヘッダーインジェクションに、
+// 非 ASCII はプロキシやキューの実装ごとの扱いの違いにつながるためです。
+func checkPropagated(key, value string, opts PropagationOptions) error {
+	if !isSafePropagationKey(key) {
+		return fmt.Errorf("%w: unsafe key %q", ErrUnsafeAttr, key)
+	}
+	if len(value) > opts.maxValueLen() {
+		return fmt.Errorf("%w: value of %q exceeds %d bytes", ErrUnsafeAttr, key, opts.maxValueLen())
+	}
+	for i := 0; i < len(value); i++ {
+		if c := value[i]; c < ' ' || c > '~' {
+			return fmt.Errorf("%w: value of %q contains non-printable characters", ErrUnsafeAttr, key)
+		}
+	}
+	return nil
+}
+
+func isSafePropagationKey(key string) bool {
+	if key == "" || len(key) > maxPropagationKeyLength {
+		return false
+	}
+	for _, r := range key {
+		if !isASCIIAlphanumeric(r) && r != '_' && r != '-' && r != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+func isASCIIAlphanumeric(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
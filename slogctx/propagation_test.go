@@ -0,0 +1,172 @@
+package slogctx
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	opts := PropagationOptions{Allow: []string{"job_id", "tenant", "attempt"}}
+
+	ctx := With(context.Background(),
+		slog.String("job_id", "job-1"),
+		slog.String("secret", "not-allowed"),
+		slog.Int("attempt", 1),
+	)
+	ctx = With(ctx, slog.Int("attempt", 2))
+
+	carrier, err := EncodeAttrs(ctx, opts)
+	if err != nil {
+		t.Fatalf("EncodeAttrs() error = %v", err)
+	}
+	want := map[string]string{"x-slogctx-job-id": "job-1", "x-slogctx-attempt": "2"}
+	if len(carrier) != len(want) {
+		t.Fatalf("EncodeAttrs() = %v, want %v", carrier, want)
+	}
+	for k, v := range want {
+		if carrier[k] != v {
+			t.Errorf("carrier[%q] = %q, want %q", k, carrier[k], v)
+		}
+	}
+
+	// 受け取る側では Allow の順に文字列として復元されること。
+	decoded, err := DecodeAttrs(context.Background(), carrier, opts)
+	if err != nil {
+		t.Fatalf("DecodeAttrs() error = %v", err)
+	}
+	got := Attrs(decoded)
+	if len(got) != 2 || !got[0].Equal(slog.String("job_id", "job-1")) || !got[1].Equal(slog.String("attempt", "2")) {
+		t.Errorf("Attrs() = %v, want [job_id=job-1 attempt=2]", got)
+	}
+}
+
+func TestDecodeHeader(t *testing.T) {
+	opts := PropagationOptions{Allow: []string{"job_id"}, Prefix: "X-Job-"}
+
+	carrier, err := EncodeAttrs(With(context.Background(), slog.String("job_id", "job-1")), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := http.Header{}
+	for k, v := range carrier {
+		h.Set(k, v) // ヘッダー名は正規化されて大文字小文字が変わる
+	}
+	h.Set("Authorization", "Bearer token")
+
+	ctx, err := DecodeHeader(context.Background(), h, opts)
+	if err != nil {
+		t.Fatalf("DecodeHeader() error = %v", err)
+	}
+	if got := Attrs(ctx); len(got) != 1 || got[0].Value.String() != "job-1" {
+		t.Errorf("Attrs() = %v, want [job_id=job-1]", got)
+	}
+}
+
+// マップのキーは、アンダースコアやドットを捨てるプロキシを通れる文字だけであること。
+func TestCarrierNamesAreProxySafe(t *testing.T) {
+	opts := PropagationOptions{Allow: []string{"job_id", "trace.span", "Tenant-ID"}}
+	ctx := With(context.Background(),
+		slog.String("job_id", "job-1"),
+		slog.String("trace.span", "s-1"),
+		slog.String("Tenant-ID", "t-1"),
+	)
+	carrier, err := EncodeAttrs(ctx, opts)
+	if err != nil {
+		t.Fatalf("EncodeAttrs() error = %v", err)
+	}
+	want := map[string]string{"x-slogctx-job-id": "job-1", "x-slogctx-trace-span": "s-1", "x-slogctx-tenant-id": "t-1"}
+	if len(carrier) != len(want) {
+		t.Fatalf("EncodeAttrs() = %v, want %v", carrier, want)
+	}
+	for k, v := range want {
+		if carrier[k] != v {
+			t.Errorf("carrier[%q] = %q, want %q", k, carrier[k], v)
+		}
+	}
+
+	decoded, err := DecodeAttrs(context.Background(), carrier, opts)
+	if err != nil {
+		t.Fatalf("DecodeAttrs() error = %v", err)
+	}
+	if got := Attrs(decoded); len(got) != 3 || got[0].Key != "job_id" || got[1].Key != "trace.span" || got[2].Key != "Tenant-ID" {
+		t.Errorf("Attrs() = %v, want 元のキーで復元", got)
+	}
+}
+
+// 同じマップのキーになる Allow のキーは区別できないため、どちらも伝播しないこと。
+func TestAmbiguousCarrierNames(t *testing.T) {
+	opts := PropagationOptions{Allow: []string{"job_id", "job-id", "job_id", "tenant"}}
+	ctx := With(context.Background(),
+		slog.String("job_id", "a"),
+		slog.String("job-id", "b"),
+		slog.String("tenant", "t-1"),
+	)
+	carrier, err := EncodeAttrs(ctx, opts)
+	if !errors.Is(err, ErrUnsafeAttr) {
+		t.Fatalf("EncodeAttrs() error = %v, want ErrUnsafeAttr", err)
+	}
+	if len(carrier) != 1 || carrier["x-slogctx-tenant"] != "t-1" {
+		t.Errorf("EncodeAttrs() = %v, want tenant のみ", carrier)
+	}
+
+	decoded, err := DecodeAttrs(context.Background(), map[string]string{"x-slogctx-job-id": "a", "x-slogctx-tenant": "t-1"}, opts)
+	if !errors.Is(err, ErrUnsafeAttr) {
+		t.Fatalf("DecodeAttrs() error = %v, want ErrUnsafeAttr", err)
+	}
+	if got := Attrs(decoded); len(got) != 1 || got[0].Key != "tenant" {
+		t.Errorf("Attrs() = %v, want [tenant]", got)
+	}
+}
+
+func TestEncodeAttrsRejectsUnsafe(t *testing.T) {
+	opts := PropagationOptions{
+		Allow:       []string{"job_id", "bad key", "note", "long", "group"},
+		MaxValueLen: 16,
+	}
+	ctx := With(context.Background(),
+		slog.String("job_id", "job-1"),
+		slog.String("bad key", "x"),
+		slog.String("note", "line1\r\nX-Injected: 1"),
+		slog.String("long", strings.Repeat("a", 17)),
+		slog.Group("group", slog.String("a", "b")),
+	)
+
+	carrier, err := EncodeAttrs(ctx, opts)
+	if !errors.Is(err, ErrUnsafeAttr) {
+		t.Fatalf("EncodeAttrs() error = %v, want ErrUnsafeAttr", err)
+	}
+	if len(carrier) != 1 || carrier["x-slogctx-job-id"] != "job-1" {
+		t.Errorf("EncodeAttrs() = %v, want job_id のみ", carrier)
+	}
+}
+
+func TestDecodeAttrsRejectsUnsafe(t *testing.T) {
+	opts := PropagationOptions{Allow: []string{"job_id", "note"}, MaxTotalLen: 40}
+	carrier := map[string]string{
+		"x-slogctx-job-id": "job-1",
+		"x-slogctx-note":   "日本語",
+		"x-slogctx-other":  "ignored",
+	}
+
+	ctx, err := DecodeAttrs(context.Background(), carrier, opts)
+	if !errors.Is(err, ErrUnsafeAttr) {
+		t.Fatalf("DecodeAttrs() error = %v, want ErrUnsafeAttr", err)
+	}
+	if got := Attrs(ctx); len(got) != 1 || got[0].Key != "job_id" {
+		t.Errorf("Attrs() = %v, want [job_id]", got)
+	}
+
+	// 全体の上限を超える分は積まないこと。
+	carrier = map[string]string{"x-slogctx-job-id": "job-1", "x-slogctx-note": strings.Repeat("n", 20)}
+	ctx, err = DecodeAttrs(context.Background(), carrier, opts)
+	if !errors.Is(err, ErrUnsafeAttr) {
+		t.Errorf("DecodeAttrs() error = %v, want ErrUnsafeAttr", err)
+	}
+	if got := Attrs(ctx); len(got) != 1 {
+		t.Errorf("Attrs() = %v, want [job_id]", got)
+	}
+}
@@ -0,0 +1,222 @@
+package slogctx
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// Recorder は、受け取ったレコードをメモリへ記録する slog.Handler です。テストでの検証に使います。
+//
+// bytes.Buffer と JSON ハンドラーで出力してから復号し直す代わりに、解決済みの属性を
+// そのまま取り出せます。logger.With / WithGroup で積んだ属性も、context に積んだ属性も
+// Entry.Attrs に含まれます。context 属性は Recorder 自身が付与するため、NewHandler で
+// 包まなくても構いません（包んでも二重には付きません）。
+//
+// レベルによる絞り込みは行わず、すべてのレコードを記録します。
+// WithAttrs / WithGroup で派生したハンドラーは記録先を共有し、並行に使っても安全です。
+type Recorder struct {
+	store *recordStore
+	goas  []groupOrAttrs
+}
+
+// recordStore は派生したハンドラー間で共有される記録先です。
+type recordStore struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// groupOrAttrs は WithGroup / WithAttrs の呼び出し 1 回分です。どちらか一方だけが設定されます。
+type groupOrAttrs struct {
+	group string
+	attrs []slog.Attr
+}
+
+// Entry は Recorder が記録した 1 件のログです。
+type Entry struct {
+	// Record は受け取ったレコードの複製です。logger.With で積んだ属性は含みません。
+	Record slog.Record
+
+	// Attrs は logger.With、WithGroup、context 属性まで含めて解決済みの属性です。
+	// グループは slog.Group の値として入れ子になり、空の属性と空のグループは除かれます。
+	Attrs []slog.Attr
+}
+
+// Level / Message はレコードのレベルとメッセージを返します。
+func (e Entry) Level() slog.Level { return e.Record.Level }
+
+func (e Entry) Message() string { return e.Record.Message }
+
+// AttrValue は key に対応する属性の値を返します。
+// グループ内の属性は "req.id" のようにドット区切りで辿ります。キー自体にドットを含む属性
+// （"logging.googleapis.com/trace" など）は、完全一致するものを優先します。
+func (e Entry) AttrValue(key string) (slog.Value, bool) {
+	return lookupAttr(e.Attrs, key)
+}
+
+func lookupAttr(attrs []slog.Attr, key string) (slog.Value, bool) {
+	for _, a := range attrs {
+		if a.Key == key {
+			return a.Value, true
+		}
+	}
+	for _, a := range attrs {
+		if a.Value.Kind() != slog.KindGroup {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(key, a.Key+"."); ok {
+			if v, ok := lookupAttr(a.Value.Group(), rest); ok {
+				return v, true
+			}
+		}
+	}
+	return slog.Value{}, false
+}
+
+// NewRecorder は空の Recorder を返します。
+func NewRecorder() *Recorder {
+	return &Recorder{store: &recordStore{}}
+}
+
+// Enabled はレベルに関わらず true を返します。
+func (r *Recorder) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+// Handle はレコードの属性を解決して記録します。
+func (r *Recorder) Handle(ctx context.Context, record slog.Record) error {
+	attrs := recordAttrs(record)
+	attrs = append(attrs, attrsFrom(ctx)...)
+	attrs = resolveAttrs(attrs)
+
+	// 内側のグループから順に包んでいきます。
+	for i := len(r.goas) - 1; i >= 0; i-- {
+		goa := r.goas[i]
+		if goa.group == "" {
+			attrs = append(resolveAttrs(goa.attrs), attrs...)
+			continue
+		}
+		if len(attrs) == 0 {
+			continue
+		}
+		attrs = []slog.Attr{{Key: goa.group, Value: slog.GroupValue(attrs...)}}
+	}
+
+	entry := Entry{Record: record.Clone(), Attrs: attrs}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	r.store.entries = append(r.store.entries, entry)
+	return nil
+}
+
+// WithAttrs / WithGroup は記録先を共有したまま、積んだ属性とグループを引き継いだ Recorder を返します。
+func (r *Recorder) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return r
+	}
+	return r.with(groupOrAttrs{attrs: attrs})
+}
+
+func (r *Recorder) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return r
+	}
+	return r.with(groupOrAttrs{group: name})
+}
+
+func (r *Recorder) with(goa groupOrAttrs) *Recorder {
+	goas := make([]groupOrAttrs, 0, len(r.goas)+1)
+	goas = append(goas, r.goas...)
+	goas = append(goas, goa)
+	return &Recorder{store: r.store, goas: goas}
+}
+
+// Entries は記録済みのログを記録順に返します。戻り値は複製で、以降の記録の影響を受けません。
+func (r *Recorder) Entries() []Entry {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return append([]Entry(nil), r.store.entries...)
+}
+
+// Reset は記録済みのログを破棄します。
+func (r *Recorder) Reset() {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	r.store.entries = nil
+}
+
+// Find は、レベルとメッセージが一致する最初のログを返します。
+func (r *Recorder) Find(level slog.Level, msg string) (Entry, bool) {
+	for _, e := range r.Entries() {
+		if e.Level() == level && e.Message() == msg {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// TestingT は RequireLogged が使う *testing.T の部分集合です。
+// ライブラリのコードから testing パッケージを参照しないためにインターフェースで受け取ります。
+type TestingT interface {
+	Helper()
+	Fatalf(format string, args ...any)
+}
+
+// RequireLogged は、レベルとメッセージが一致し、attrs をすべて含むログが記録されていることを確かめます。
+// 見つからなければ記録済みのログを添えて t.Fatalf で失敗させます。attrs のキーは AttrValue と同じく
+// ドット区切りでグループを辿ります。
+func (r *Recorder) RequireLogged(t TestingT, level slog.Level, msg string, attrs ...slog.Attr) Entry {
+	t.Helper()
+
+	entries := r.Entries()
+	for _, e := range entries {
+		if e.Level() == level && e.Message() == msg && hasAttrs(e, attrs) {
+			return e
+		}
+	}
+
+	var logged strings.Builder
+	for _, e := range entries {
+		logged.WriteString("\n\t")
+		logged.WriteString(e.Level().String() + " " + e.Message() + " " + slog.GroupValue(e.Attrs...).String())
+	}
+	t.Fatalf("no %s %q log with %v; logged:%s", level, msg, attrs, logged.String())
+	return Entry{}
+}
+
+func hasAttrs(e Entry, attrs []slog.Attr) bool {
+	for _, want := range attrs {
+		got, ok := e.AttrValue(want.Key)
+		if !ok || !got.Resolve().Equal(want.Value.Resolve()) {
+			return false
+		}
+	}
+	return true
+}
+
+// resolveAttrs は LogValuer を解決し、空の属性と空のグループを除きます。
+// キーが空のグループは親へ展開します。
+func resolveAttrs(attrs []slog.Attr) []slog.Attr {
+	resolved := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		a.Value = a.Value.Resolve()
+		if a.Value.Kind() == slog.KindGroup {
+			members := resolveAttrs(a.Value.Group())
+			if len(members) == 0 {
+				continue
+			}
+			if a.Key == "" {
+				resolved = append(resolved, members...)
+				continue
+			}
+			a.Value = slog.GroupValue(members...)
+		}
+		if a.Equal(slog.Attr{}) {
+			continue
+		}
+		resolved = append(resolved, a)
+	}
+	return resolved
+}
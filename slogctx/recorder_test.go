@@ -0,0 +1,137 @@
+package slogctx
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"testing"
+	"testing/slogtest"
+)
+
+// entryMap は slogtest が要求する形式へ Entry を変換します。
+func entryMap(e Entry) map[string]any {
+	m := map[string]any{
+		slog.LevelKey:   e.Level(),
+		slog.MessageKey: e.Message(),
+	}
+	if !e.Record.Time.IsZero() {
+		m[slog.TimeKey] = e.Record.Time
+	}
+	addAttrsToMap(m, e.Attrs)
+	return m
+}
+
+func addAttrsToMap(m map[string]any, attrs []slog.Attr) {
+	for _, a := range attrs {
+		if a.Value.Kind() == slog.KindGroup {
+			group := map[string]any{}
+			addAttrsToMap(group, a.Value.Group())
+			m[a.Key] = group
+			continue
+		}
+		m[a.Key] = a.Value.Any()
+	}
+}
+
+func TestRecorderSlogtest(t *testing.T) {
+	var rec *Recorder
+	slogtest.Run(t, func(*testing.T) slog.Handler {
+		rec = NewRecorder()
+		return rec
+	}, func(t *testing.T) map[string]any {
+		entries := rec.Entries()
+		if len(entries) != 1 {
+			t.Fatalf("entries = %d, want 1", len(entries))
+		}
+		return entryMap(entries[0])
+	})
+}
+
+func TestRecorderIncludesContextAndLoggerAttrs(t *testing.T) {
+	rec := NewRecorder()
+	logger := slog.New(rec).With("component", "pipeline").WithGroup("req")
+
+	ctx := With(context.Background(), slog.String("job_id", "job-1"))
+	logger.InfoContext(ctx, "phase started", slog.String("id", "r-1"))
+
+	e, ok := rec.Find(slog.LevelInfo, "phase started")
+	if !ok {
+		t.Fatal("Find() がログを見つけられない")
+	}
+	for key, want := range map[string]string{
+		"component":  "pipeline",
+		"req.id":     "r-1",
+		"req.job_id": "job-1",
+	} {
+		if got, ok := e.AttrValue(key); !ok || got.String() != want {
+			t.Errorf("AttrValue(%q) = %v, %v, want %q", key, got, ok, want)
+		}
+	}
+	if _, ok := e.AttrValue("req.missing"); ok {
+		t.Error("存在しないキーが見つかったことになっている")
+	}
+}
+
+// NewHandler で包んでも context 属性が二重に記録されないこと。
+func TestRecorderUnderNewHandler(t *testing.T) {
+	rec := NewRecorder()
+	slog.New(NewHandler(rec)).InfoContext(With(context.Background(), slog.String("job_id", "job-1")), "msg")
+
+	e := rec.RequireLogged(t, slog.LevelInfo, "msg", slog.String("job_id", "job-1"))
+	if len(e.Attrs) != 1 {
+		t.Errorf("Attrs = %v, want job_id のみ", e.Attrs)
+	}
+}
+
+// fakeT は RequireLogged の失敗を捕まえるための TestingT です。
+type fakeT struct {
+	failed string
+}
+
+func (*fakeT) Helper() {}
+
+func (f *fakeT) Fatalf(format string, args ...any) { f.failed = fmt.Sprintf(format, args...) }
+
+func TestRequireLoggedFails(t *testing.T) {
+	rec := NewRecorder()
+	slog.New(rec).Warn("retrying", slog.Int("attempt", 2))
+
+	ft := &fakeT{}
+	rec.RequireLogged(ft, slog.LevelWarn, "retrying", slog.Int("attempt", 2))
+	if ft.failed != "" {
+		t.Errorf("一致するログがあるのに失敗した: %s", ft.failed)
+	}
+
+	rec.RequireLogged(ft, slog.LevelWarn, "retrying", slog.Int("attempt", 3))
+	if ft.failed == "" {
+		t.Error("属性が一致しないのに失敗しなかった")
+	}
+}
+
+func TestRecorderReset(t *testing.T) {
+	rec := NewRecorder()
+	slog.New(rec).Info("msg")
+	rec.Reset()
+	if got := rec.Entries(); len(got) != 0 {
+		t.Errorf("Reset() 後の Entries() = %v, want 空", got)
+	}
+}
+
+// 派生したハンドラーから並行に記録しても取りこぼさないこと（-race で検証）。
+func TestRecorderConcurrent(t *testing.T) {
+	rec := NewRecorder()
+	logger := slog.New(rec)
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Go(func() {
+			logger.With("worker", i).Info("tick")
+		})
+	}
+	wg.Wait()
+
+	if got := len(rec.Entries()); got != 10 {
+		t.Errorf("entries = %d, want 10", got)
+	}
+}
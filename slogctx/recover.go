@@ -0,0 +1,81 @@
+package slogctx
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+)
+
+// PanicError は、Recover が panic から変換したエラーです。errors.As で取り出せます。
+type PanicError struct {
+	// Value は recover が返した値です。
+	Value any
+	// Stack は panic した goroutine の呼び出し履歴です。
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap は、panic の値がエラーならそれを返します。
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// Recover は panic を回復し、その値と呼び出し履歴を ctx の属性とともに ERROR で記録します。
+// defer で直接呼んでください（別の関数を挟むと recover が効きません）。
+//
+//	func (w *Worker) run(ctx context.Context) (err error) {
+//		defer slogctx.Recover(ctx, w.logger, &err)
+//		...
+//	}
+//
+// errp が nil でなければ、panic を *PanicError に変換して *errp へ設定し、処理を続けます。
+// errp が nil なら、記録したあとで同じ値で panic し直します。いずれの場合も、ジョブ ID などの
+// context 属性が付いた形でクラッシュの原因がログに残ります。logger が nil なら slog.Default() を使います。
+func Recover(ctx context.Context, logger *slog.Logger, errp *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	perr := &PanicError{Value: r, Stack: debug.Stack()}
+	logPanic(ctx, logger, perr)
+
+	if errp == nil {
+		panic(r)
+	}
+	*errp = perr
+}
+
+// Go は fn を新しい goroutine で実行し、その結果を返すチャネルを返します。
+//
+// fn が panic した場合は Recover と同じく記録したうえで *PanicError に変換し、プロセスを
+// 落とさずにチャネルへ送ります。fn が返したエラーは記録せずにそのまま送ります。チャネルには
+// 値が 1 つだけ送られたあと閉じられ、受け取らなくても goroutine は終了します。
+func Go(ctx context.Context, fn func(ctx context.Context) error) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		defer close(done)
+		var err error
+		func() {
+			defer Recover(ctx, nil, &err)
+			err = fn(ctx)
+		}()
+		done <- err
+	}()
+	return done
+}
+
+func logPanic(ctx context.Context, logger *slog.Logger, perr *PanicError) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.LogAttrs(ctx, slog.LevelError, "panic recovered",
+		slog.Any("panic", perr.Value),
+		slog.String("stack", string(perr.Stack)),
+	)
+}
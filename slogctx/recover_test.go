@@ -0,0 +1,93 @@
+package slogctx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRecoverConvertsPanic(t *testing.T) {
+	rec := NewRecorder()
+	logger := slog.New(rec)
+	ctx := With(context.Background(), slog.String("job_id", "job-1"))
+
+	run := func() (err error) {
+		defer Recover(ctx, logger, &err)
+		panic(io.ErrUnexpectedEOF)
+	}
+	err := run()
+
+	var perr *PanicError
+	if !errors.As(err, &perr) {
+		t.Fatalf("run() error = %v, want *PanicError", err)
+	}
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Error("panic の値のエラーを errors.Is で判定できない")
+	}
+	if !strings.Contains(string(perr.Stack), "TestRecoverConvertsPanic") {
+		t.Errorf("呼び出し履歴に panic した関数が含まれない:\n%s", perr.Stack)
+	}
+
+	e := rec.RequireLogged(t, slog.LevelError, "panic recovered", slog.String("job_id", "job-1"))
+	if v, ok := e.AttrValue("panic"); !ok || v.Any() != io.ErrUnexpectedEOF {
+		t.Errorf("panic 属性 = %v, want %v", v, io.ErrUnexpectedEOF)
+	}
+	if _, ok := e.AttrValue("stack"); !ok {
+		t.Error("stack 属性が出力されていない")
+	}
+}
+
+func TestRecoverRepanics(t *testing.T) {
+	rec := NewRecorder()
+
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("recover() = %v, want boom", r)
+		}
+		rec.RequireLogged(t, slog.LevelError, "panic recovered", slog.String("panic", "boom"))
+	}()
+
+	func() {
+		defer Recover(context.Background(), slog.New(rec), nil)
+		panic("boom")
+	}()
+	t.Error("panic し直されていない")
+}
+
+func TestRecoverWithoutPanic(t *testing.T) {
+	rec := NewRecorder()
+	run := func() (err error) {
+		defer Recover(context.Background(), slog.New(rec), &err)
+		return io.EOF
+	}
+	if err := run(); !errors.Is(err, io.EOF) {
+		t.Errorf("run() error = %v, want io.EOF（panic が無ければ変えない）", err)
+	}
+	if n := len(rec.Entries()); n != 0 {
+		t.Errorf("panic が無いのにログが出力された: %d 件", n)
+	}
+}
+
+func TestGo(t *testing.T) {
+	rec := NewRecorder()
+	prev := slog.Default()
+	slog.SetDefault(slog.New(rec))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	ctx := With(context.Background(), slog.String("job_id", "job-1"))
+
+	err := <-Go(ctx, func(context.Context) error { return io.EOF })
+	if !errors.Is(err, io.EOF) {
+		t.Errorf("Go() の結果 = %v, want io.EOF", err)
+	}
+
+	err = <-Go(ctx, func(context.Context) error { panic("worker crashed") })
+	var perr *PanicError
+	if !errors.As(err, &perr) || perr.Value != "worker crashed" {
+		t.Errorf("Go() の結果 = %v, want *PanicError(worker crashed)", err)
+	}
+	rec.RequireLogged(t, slog.LevelError, "panic recovered", slog.String("job_id", "job-1"))
+}
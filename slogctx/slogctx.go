@@ -6,29 +6,24 @@
 // 標準の slog.Logger.With はロガーを引き回す必要がありますが、こちらは context に
 // 乗るため、既存の slog.XxxContext(ctx, ...) 呼び出しをそのまま相関ログにできます。
 //
-// 出力フォーマットには関与しません。GCP の Cloud Logging 向けに severity などを
-// 詰め替える場合は、その HandlerOptions を持つハンドラーを NewHandler で包んでください。
+// NewHandler 自体は出力フォーマットに関与しません。GCP の Cloud Logging 向けに severity などを
+// 詰め替える場合は、NewCloudLoggingHandler を使ってください。
 package slogctx
 
 import (
 	"context"
 	"log/slog"
-	"strings"
 )
 
 // ParseLevel は環境変数などの文字列を slog のレベルへ変換します。
-// 前後の空白は無視し、大文字小文字は区別しません。未知の値と空文字は Info とみなします。
+// 前後の空白は無視し、大文字小文字は区別しません。"INFO+2" のようなオフセットも受け付けます。
+// 未知の値と空文字は Info とみなします。誤りをエラーとして扱いたい場合は ParseLevelSpec を使ってください。
 func ParseLevel(raw string) slog.Level {
-	switch strings.ToUpper(strings.TrimSpace(raw)) {
-	case "DEBUG":
-		return slog.LevelDebug
-	case "WARN", "WARNING":
-		return slog.LevelWarn
-	case "ERROR":
-		return slog.LevelError
-	default:
+	level, err := parseLevelToken(raw)
+	if err != nil {
 		return slog.LevelInfo
 	}
+	return level
 }
 
 type contextKey struct{}
@@ -64,30 +59,127 @@ func attrsFrom(ctx context.Context) []slog.Attr {
 	return attrs
 }
 
+// Option は NewHandler の動作を変更します。
+type Option func(*handlerOptions)
+
+// handlerOptions は NewHandler に渡されたオプションをまとめたものです。
+type handlerOptions struct {
+	errorStack bool
+	extractors []Extractor
+	dedup      *[SourceCall + 1]int
+}
+
+// WithErrorStack は、エラーを値に持つ属性に、WrapError が記録した呼び出し履歴を添えます。
+// 呼び出し履歴は "{属性のキー}_stack" のキーで、エラーが持つ属性の後ろに出力します。
+func WithErrorStack() Option {
+	return func(o *handlerOptions) {
+		o.errorStack = true
+	}
+}
+
 // NewHandler は、context に積まれた属性をレコードへ付与するハンドラーで base を包みます。
-func NewHandler(base slog.Handler) slog.Handler {
-	return &handler{Handler: base}
+//
+// あわせて、エラーを値に持つ属性を見つけると、WrapError でそのエラーに持たせた属性を
+// 直後に展開します。
+func NewHandler(base slog.Handler, opts ...Option) slog.Handler {
+	o := &handlerOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return &handler{Handler: base, opts: o}
 }
 
 // handler は context 由来の属性をレコードへ付与する slog.Handler です。
 type handler struct {
 	slog.Handler
+	opts *handlerOptions
+
+	// goas は WithDedup を指定したときだけ使う、委譲先へ渡さずに保持した WithAttrs / WithGroup です。
+	goas []groupOrAttrs
 }
 
 // Handle は context 由来の属性を足したうえで委譲先のハンドラーへ渡します。
+// Extractor の属性、With で積んだ属性の順に、呼び出し時の属性の後ろへ足します。
+//
+// 委譲先へは属性を取り除いた context を渡します。NewFanout の各シンクのように
+// 内側にも NewHandler が挟まっている構成で、同じ属性が二重に付くのを防ぐためです。
 func (h *handler) Handle(ctx context.Context, record slog.Record) error {
-	if attrs := attrsFrom(ctx); len(attrs) > 0 {
+	if h.opts.dedup != nil {
+		return h.handleDedup(ctx, record)
+	}
+	attrs := attrsFrom(ctx)
+	if len(h.opts.extractors) > 0 {
+		record.AddAttrs(extract(ctx, h.opts.extractors, attrs)...)
+	}
+	if len(attrs) > 0 {
 		record.AddAttrs(attrs...)
+		ctx = withoutAttrs(ctx)
+	}
+	if hasAnyAttr(record) {
+		if expanded, ok := expandErrorAttrs(recordAttrs(record), h.opts.errorStack); ok {
+			record = rebuildRecord(record, expanded)
+		}
 	}
 	return h.Handler.Handle(ctx, record)
 }
 
+// withoutAttrs は、付与済みの属性を内側のハンドラーから見えなくした context を返します。
+func withoutAttrs(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKey{}, []slog.Attr(nil))
+}
+
 // WithAttrs / WithGroup は委譲先を包み直し、context 属性の付与を維持します。
 // 包み直さないと、logger.With(...) を通した時点で context 由来の属性が失われます。
+// WithDedup を指定した場合は委譲先へ渡さず、Handle でまとめるために保持します。
 func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	return &handler{Handler: h.Handler.WithAttrs(attrs)}
+	attrs, _ = expandErrorAttrs(attrs, h.opts.errorStack)
+	if h.opts.dedup != nil {
+		return h.with(groupOrAttrs{attrs: attrs})
+	}
+	return &handler{Handler: h.Handler.WithAttrs(attrs), opts: h.opts}
 }
 
 func (h *handler) WithGroup(name string) slog.Handler {
-	return &handler{Handler: h.Handler.WithGroup(name)}
+	if h.opts.dedup != nil {
+		if name == "" {
+			return h
+		}
+		return h.with(groupOrAttrs{group: name})
+	}
+	return &handler{Handler: h.Handler.WithGroup(name), opts: h.opts}
+}
+
+func (h *handler) with(goa groupOrAttrs) *handler {
+	goas := make([]groupOrAttrs, 0, len(h.goas)+1)
+	goas = append(goas, h.goas...)
+	goas = append(goas, goa)
+	return &handler{Handler: h.Handler, opts: h.opts, goas: goas}
+}
+
+// hasAnyAttr は、エラーを含みうる KindAny の属性がレコードにあるかを返します。
+// 大半のレコードは該当しないため、属性を取り出す前にこれで振り分けます。
+func hasAnyAttr(record slog.Record) bool {
+	found := false
+	record.Attrs(func(a slog.Attr) bool {
+		found = a.Value.Kind() == slog.KindAny
+		return !found
+	})
+	return found
+}
+
+// recordAttrs はレコードの属性をスライスで返します。
+func recordAttrs(record slog.Record) []slog.Attr {
+	attrs := make([]slog.Attr, 0, record.NumAttrs())
+	record.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	return attrs
+}
+
+// rebuildRecord は、属性だけを attrs に差し替えたレコードを返します。
+func rebuildRecord(record slog.Record, attrs []slog.Attr) slog.Record {
+	rebuilt := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	rebuilt.AddAttrs(attrs...)
+	return rebuilt
 }
@@ -42,6 +42,9 @@ func TestParseLevel(t *testing.T) {
 		{"ERROR", slog.LevelError},
 		{"", slog.LevelInfo},
 		{"nonsense", slog.LevelInfo},
+		{"INFO+2", slog.LevelInfo + 2},
+		{"debug-4", slog.LevelDebug - 4},
+		{"INFO+x", slog.LevelInfo},
 	}
 
 	for _, tt := range tests {
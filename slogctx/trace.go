@@ -0,0 +1,206 @@
+package slogctx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidTraceHeader は、トレースヘッダーを解釈できなかったことを表します。
+// errors.Is で判定できます。
+var ErrInvalidTraceHeader = errors.New("invalid trace header")
+
+// トレースの伝播に使われるヘッダー名です。
+const (
+	// HeaderTraceparent は W3C Trace Context のヘッダーです。
+	HeaderTraceparent = "traceparent"
+
+	// HeaderCloudTraceContext は Google Cloud の従来形式のヘッダーです。
+	HeaderCloudTraceContext = "X-Cloud-Trace-Context"
+)
+
+// TraceContext はリクエストのトレース ID とスパン ID です。
+// ID は小文字の 16 進数で、TraceID は 32 桁、SpanID は 16 桁です。
+type TraceContext struct {
+	TraceID string
+	SpanID  string
+	Sampled bool
+}
+
+// IsValid は TraceID が W3C Trace Context の形式を満たすかを返します。
+// SpanID は X-Cloud-Trace-Context で省略されうるため、空なら問いません。
+func (tc TraceContext) IsValid() bool {
+	if !isHexID(tc.TraceID, 32) {
+		return false
+	}
+	return tc.SpanID == "" || isHexID(tc.SpanID, 16)
+}
+
+// Traceparent は traceparent ヘッダーの値を返します。SpanID が空なら空文字を返します。
+func (tc TraceContext) Traceparent() string {
+	if !tc.IsValid() || tc.SpanID == "" {
+		return ""
+	}
+	flags := "00"
+	if tc.Sampled {
+		flags = "01"
+	}
+	return "00-" + tc.TraceID + "-" + tc.SpanID + "-" + flags
+}
+
+// ParseTraceparent は W3C Trace Context の traceparent ヘッダーを解釈します。
+//
+// バージョン 00 は厳密に検査し、それより新しいバージョンは仕様どおり先頭 4 フィールドだけを読みます。
+// 解釈できない場合は ErrInvalidTraceHeader をラップしたエラーを返します。
+func ParseTraceparent(header string) (TraceContext, error) {
+	value := strings.TrimSpace(header)
+	fields := strings.Split(value, "-")
+	if len(fields) < 4 {
+		return TraceContext{}, fmt.Errorf("%w: %q", ErrInvalidTraceHeader, header)
+	}
+
+	version, traceID, spanID, flags := fields[0], fields[1], fields[2], fields[3]
+	valid := isHex(version, 2) && version != "ff" &&
+		isHexID(traceID, 32) && isHexID(spanID, 16) && isHex(flags, 2)
+	if version == "00" && len(fields) != 4 {
+		valid = false
+	}
+	if !valid {
+		return TraceContext{}, fmt.Errorf("%w: %q", ErrInvalidTraceHeader, header)
+	}
+
+	flagBits, _ := strconv.ParseUint(flags, 16, 8)
+	return TraceContext{TraceID: traceID, SpanID: spanID, Sampled: flagBits&0x01 == 1}, nil
+}
+
+// ParseCloudTraceContext は X-Cloud-Trace-Context ヘッダー（TRACE_ID/SPAN_ID;o=OPTIONS）を解釈します。
+//
+// SPAN_ID は 10 進数で送られてくるため、traceparent と揃えて 16 桁の 16 進数へ変換します。
+// SPAN_ID と OPTIONS は省略できます。解釈できない場合は ErrInvalidTraceHeader をラップしたエラーを返します。
+func ParseCloudTraceContext(header string) (TraceContext, error) {
+	value := strings.TrimSpace(header)
+	value, options, _ := strings.Cut(value, ";")
+	traceID, span, hasSpan := strings.Cut(value, "/")
+
+	tc := TraceContext{TraceID: strings.ToLower(traceID)}
+	if !isHexID(tc.TraceID, 32) {
+		return TraceContext{}, fmt.Errorf("%w: %q", ErrInvalidTraceHeader, header)
+	}
+	if hasSpan {
+		n, err := strconv.ParseUint(span, 10, 64)
+		if err != nil || n == 0 {
+			return TraceContext{}, fmt.Errorf("%w: %q", ErrInvalidTraceHeader, header)
+		}
+		tc.SpanID = fmt.Sprintf("%016x", n)
+	}
+	tc.Sampled = options == "o=1"
+	return tc, nil
+}
+
+// TraceFromHeader は、traceparent を優先し、なければ X-Cloud-Trace-Context からトレースを取り出します。
+// どちらも無いか解釈できなければ false を返します。
+func TraceFromHeader(h http.Header) (TraceContext, bool) {
+	if v := h.Get(HeaderTraceparent); v != "" {
+		if tc, err := ParseTraceparent(v); err == nil {
+			return tc, true
+		}
+	}
+	if v := h.Get(HeaderCloudTraceContext); v != "" {
+		if tc, err := ParseCloudTraceContext(v); err == nil {
+			return tc, true
+		}
+	}
+	return TraceContext{}, false
+}
+
+// NewTraceContext は乱数から新しいトレース ID とスパン ID を生成します。
+// 上流からトレースが渡されなかったリクエストやジョブの起点で使います。
+func NewTraceContext() (TraceContext, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return TraceContext{}, fmt.Errorf("trace id entropy: %w", err)
+	}
+	// 全桁 0 は無効な ID なので、最下位ビットを立てて避けます。
+	buf[15] |= 0x01
+	buf[23] |= 0x01
+	return TraceContext{
+		TraceID: hex.EncodeToString(buf[:16]),
+		SpanID:  hex.EncodeToString(buf[16:]),
+	}, nil
+}
+
+// TraceKeys は、WithTrace が context へ積む属性のキーです。空のキーの属性は積みません。
+type TraceKeys struct {
+	Trace   string
+	Span    string
+	Sampled string
+
+	// ProjectID が空でなければ、トレース ID を projects/{ProjectID}/traces/{TraceID} の形式で積みます。
+	// Cloud Logging がログとトレースを関連付けるのに必要な形式です。
+	ProjectID string
+}
+
+// Cloud Logging が特別扱いする構造化ログのフィールド名です。
+const (
+	CloudTraceKey        = "logging.googleapis.com/trace"
+	CloudSpanIDKey       = "logging.googleapis.com/spanId"
+	CloudTraceSampledKey = "logging.googleapis.com/trace_sampled"
+)
+
+// CloudTraceKeys は Cloud Logging のフィールド名を使う TraceKeys を返します。
+func CloudTraceKeys(projectID string) TraceKeys {
+	return TraceKeys{
+		Trace:     CloudTraceKey,
+		Span:      CloudSpanIDKey,
+		Sampled:   CloudTraceSampledKey,
+		ProjectID: projectID,
+	}
+}
+
+// WithTrace はトレース ID とスパン ID を With で context へ積みます。
+// tc が不正なら ctx をそのまま返します。
+func WithTrace(ctx context.Context, tc TraceContext, keys TraceKeys) context.Context {
+	if !tc.IsValid() {
+		return ctx
+	}
+
+	attrs := make([]slog.Attr, 0, 3)
+	if keys.Trace != "" {
+		trace := tc.TraceID
+		if keys.ProjectID != "" {
+			trace = "projects/" + keys.ProjectID + "/traces/" + tc.TraceID
+		}
+		attrs = append(attrs, slog.String(keys.Trace, trace))
+	}
+	if keys.Span != "" && tc.SpanID != "" {
+		attrs = append(attrs, slog.String(keys.Span, tc.SpanID))
+	}
+	if keys.Sampled != "" {
+		attrs = append(attrs, slog.Bool(keys.Sampled, tc.Sampled))
+	}
+	return With(ctx, attrs...)
+}
+
+// isHexID は、n 桁の小文字 16 進数で、かつ全桁 0 ではないかを返します。
+func isHexID(value string, n int) bool {
+	return isHex(value, n) && strings.Trim(value, "0") != ""
+}
+
+// isHex は、n 桁の小文字 16 進数かを返します。
+func isHex(value string, n int) bool {
+	if len(value) != n {
+		return false
+	}
+	for _, r := range value {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
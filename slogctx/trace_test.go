@@ -0,0 +1,137 @@
+package slogctx
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	got, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if err != nil {
+		t.Fatalf("ParseTraceparent() error = %v", err)
+	}
+	want := TraceContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7", Sampled: true}
+	if got != want {
+		t.Errorf("ParseTraceparent() = %+v, want %+v", got, want)
+	}
+
+	// 新しいバージョンは先頭 4 フィールドだけを読むこと。
+	if _, err := ParseTraceparent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-extra"); err != nil {
+		t.Errorf("将来バージョンの traceparent を拒否した: %v", err)
+	}
+
+	invalid := map[string]string{
+		"":    "空文字",
+		"abc": "フィールド不足",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01":       "禁止されたバージョン",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01":       "全桁 0 のトレース ID",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01":       "全桁 0 のスパン ID",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01":       "大文字",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra": "バージョン 00 の余分なフィールド",
+	}
+	for header, reason := range invalid {
+		if _, err := ParseTraceparent(header); !errors.Is(err, ErrInvalidTraceHeader) {
+			t.Errorf("ParseTraceparent(%q) error = %v, want ErrInvalidTraceHeader (%s)", header, err, reason)
+		}
+	}
+}
+
+func TestParseCloudTraceContext(t *testing.T) {
+	tests := []struct {
+		header string
+		want   TraceContext
+	}{
+		{
+			"105445aa7843bc8bf206b12000100000/1;o=1",
+			TraceContext{TraceID: "105445aa7843bc8bf206b12000100000", SpanID: "0000000000000001", Sampled: true},
+		},
+		{
+			// スパン ID は 10 進数から 16 進数へ変換されること。
+			"105445AA7843BC8BF206B12000100000/18446744073709551615;o=0",
+			TraceContext{TraceID: "105445aa7843bc8bf206b12000100000", SpanID: "ffffffffffffffff"},
+		},
+		{
+			"105445aa7843bc8bf206b12000100000",
+			TraceContext{TraceID: "105445aa7843bc8bf206b12000100000"},
+		},
+	}
+	for _, tt := range tests {
+		got, err := ParseCloudTraceContext(tt.header)
+		if err != nil {
+			t.Errorf("ParseCloudTraceContext(%q) error = %v", tt.header, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseCloudTraceContext(%q) = %+v, want %+v", tt.header, got, tt.want)
+		}
+	}
+
+	for _, header := range []string{"", "not-hex/1", "105445aa7843bc8bf206b12000100000/abc", "105445aa7843bc8bf206b12000100000/0"} {
+		if _, err := ParseCloudTraceContext(header); !errors.Is(err, ErrInvalidTraceHeader) {
+			t.Errorf("ParseCloudTraceContext(%q) error = %v, want ErrInvalidTraceHeader", header, err)
+		}
+	}
+}
+
+func TestTraceFromHeader(t *testing.T) {
+	h := http.Header{}
+	if _, ok := TraceFromHeader(h); ok {
+		t.Error("ヘッダーが無いのに true を返した")
+	}
+
+	h.Set(HeaderCloudTraceContext, "105445aa7843bc8bf206b12000100000/1;o=1")
+	if tc, ok := TraceFromHeader(h); !ok || tc.TraceID != "105445aa7843bc8bf206b12000100000" {
+		t.Errorf("TraceFromHeader() = %+v, %v, want X-Cloud-Trace-Context のトレース", tc, ok)
+	}
+
+	// traceparent が優先されること。
+	h.Set(HeaderTraceparent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if tc, ok := TraceFromHeader(h); !ok || tc.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("TraceFromHeader() = %+v, %v, want traceparent のトレース", tc, ok)
+	}
+}
+
+func TestNewTraceContext(t *testing.T) {
+	tc, err := NewTraceContext()
+	if err != nil {
+		t.Fatalf("NewTraceContext() error = %v", err)
+	}
+	if !tc.IsValid() || tc.SpanID == "" {
+		t.Errorf("NewTraceContext() = %+v, want 有効なトレースとスパン", tc)
+	}
+
+	// 生成した値が traceparent として往復できること。
+	got, err := ParseTraceparent(tc.Traceparent())
+	if err != nil || got != tc {
+		t.Errorf("ParseTraceparent(Traceparent()) = %+v, %v, want %+v", got, err, tc)
+	}
+}
+
+func TestWithTrace(t *testing.T) {
+	tc := TraceContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7", Sampled: true}
+
+	rec := NewRecorder()
+	ctx := WithTrace(context.Background(), tc, CloudTraceKeys("my-project"))
+	slog.New(rec).InfoContext(ctx, "msg")
+
+	rec.RequireLogged(t, slog.LevelInfo, "msg",
+		slog.String(CloudTraceKey, "projects/my-project/traces/4bf92f3577b34da6a3ce929d0e0e4736"),
+		slog.String(CloudSpanIDKey, "00f067aa0ba902b7"),
+		slog.Bool(CloudTraceSampledKey, true),
+	)
+
+	// キーを空にした属性は積まないこと。
+	ctx = WithTrace(context.Background(), tc, TraceKeys{Trace: "trace_id"})
+	if attrs := Attrs(ctx); len(attrs) != 1 || attrs[0].Value.String() != tc.TraceID {
+		t.Errorf("Attrs() = %v, want trace_id のみ", attrs)
+	}
+
+	// 不正なトレースでは context を変えないこと。
+	base := context.Background()
+	if got := WithTrace(base, TraceContext{}, CloudTraceKeys("")); got != base {
+		t.Error("不正なトレースで別の context を返した")
+	}
+}
@@ -0,0 +1,116 @@
+package slogctx
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// RedactedValue は、ログへ出さないクエリの値の代わりに出力する文字列です。
+const RedactedValue = "REDACTED"
+
+// TransportOptions は NewTransport の設定です。ゼロ値のままでも使えます。
+type TransportOptions struct {
+	// Logger はログの出力先です。nil なら slog.Default() を使います。
+	Logger *slog.Logger
+
+	// AllowQuery は、値をそのままログへ出してよいクエリパラメータの名前です。
+	// それ以外のパラメータは名前だけを残し、値を RedactedValue に置き換えます。
+	// 署名付き URL のトークンや API キーがクエリに載ることがあるため、既定ではすべて伏せます。
+	AllowQuery []string
+
+	// Level はステータスとエラーから出力レベルを決めます。nil なら、エラーと 5xx が Error、
+	// 4xx が Warn、それ以外が Info です。エラーのときの status は 0 です。
+	Level func(status int, err error) slog.Level
+}
+
+// NewTransport は、送信したリクエストをログへ記録する http.RoundTripper で base を包みます。
+// base が nil なら http.DefaultTransport を使います。
+//
+// ログはリクエストの context で出力するため、With で積んだ属性により、受け付けたリクエストや
+// ジョブと外部 API の呼び出しを相関できます。記録するのはメソッド、ホスト、パス、伏せ字にした
+// クエリ、ステータス、レイテンシ、ボディの大きさ（分かる場合）と、429 や 503 の Retry-After です。
+func NewTransport(base http.RoundTripper, opts TransportOptions) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base, opts: opts}
+}
+
+// transport は送信したリクエストを記録する http.RoundTripper です。
+type transport struct {
+	base http.RoundTripper
+	opts TransportOptions
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	res, err := t.base.RoundTrip(req)
+	latency := time.Since(start)
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("host", req.URL.Host),
+		slog.String("path", req.URL.Path),
+	}
+	if query := redactQuery(req.URL.Query(), t.opts.AllowQuery); query != "" {
+		attrs = append(attrs, slog.String("query", query))
+	}
+	if req.ContentLength > 0 {
+		attrs = append(attrs, slog.Int64("request_bytes", req.ContentLength))
+	}
+
+	status := 0
+	if res != nil {
+		status = res.StatusCode
+		attrs = append(attrs, slog.Int("status", status))
+		if res.ContentLength >= 0 {
+			attrs = append(attrs, slog.Int64("response_bytes", res.ContentLength))
+		}
+		if retryAfter := res.Header.Get("Retry-After"); retryAfter != "" {
+			attrs = append(attrs, slog.String("retry_after", retryAfter))
+		}
+	}
+	attrs = append(attrs, slog.Duration("latency", latency))
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+
+	level := t.level(status, err)
+	logger := t.opts.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.LogAttrs(req.Context(), level, "http client request", attrs...)
+
+	return res, err
+}
+
+func (t *transport) level(status int, err error) slog.Level {
+	if t.opts.Level != nil {
+		return t.opts.Level(status, err)
+	}
+	if err != nil {
+		return slog.LevelError
+	}
+	return accessLogLevel(status)
+}
+
+// redactQuery は、allow に無いパラメータの値を伏せたクエリ文字列を返します。
+// キーの順に並べるため、同じクエリは常に同じ文字列になります。
+func redactQuery(query url.Values, allow []string) string {
+	if len(query) == 0 {
+		return ""
+	}
+	redacted := make(url.Values, len(query))
+	for key, values := range query {
+		if slices.Contains(allow, key) {
+			redacted[key] = values
+			continue
+		}
+		redacted[key] = slices.Repeat([]string{RedactedValue}, len(values))
+	}
+	return redacted.Encode()
+}
@@ -0,0 +1,136 @@
+package slogctx
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestTransportLogsRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/busy":
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			_, _ = w.Write([]byte("ok"))
+		}
+	}))
+	defer srv.Close()
+
+	rec := NewRecorder()
+	client := &http.Client{Transport: NewTransport(srv.Client().Transport, TransportOptions{
+		Logger:     slog.New(NewHandler(rec)),
+		AllowQuery: []string{"page"},
+	})}
+	host := strings.TrimPrefix(srv.URL, "http://")
+	ctx := With(context.Background(), slog.String("job_id", "job-1"))
+
+	tests := []struct {
+		target string
+		body   string
+		level  slog.Level
+		attrs  []slog.Attr
+	}{
+		{
+			"/objects?page=2&token=secret", "payload", slog.LevelInfo,
+			[]slog.Attr{
+				slog.String("method", http.MethodPost),
+				slog.String("host", host),
+				slog.String("path", "/objects"),
+				slog.String("query", "page=2&token="+RedactedValue),
+				slog.Int("status", http.StatusOK),
+				slog.Int64("request_bytes", 7),
+				slog.Int64("response_bytes", 2),
+				slog.String("job_id", "job-1"),
+			},
+		},
+		{"/missing", "", slog.LevelWarn, []slog.Attr{slog.Int("status", http.StatusNotFound)}},
+		{"/busy", "", slog.LevelError, []slog.Attr{slog.Int("status", http.StatusServiceUnavailable), slog.String("retry_after", "30")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			rec.Reset()
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL+tt.target, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			res, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_ = res.Body.Close()
+
+			e := rec.RequireLogged(t, tt.level, "http client request", tt.attrs...)
+			if _, ok := e.AttrValue("latency"); !ok {
+				t.Error("latency が出力されていない")
+			}
+		})
+	}
+
+	// クエリが無ければ query 属性を出さないこと。
+	rec.Reset()
+	res, err := client.Get(srv.URL + "/plain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = res.Body.Close()
+	e := rec.RequireLogged(t, slog.LevelInfo, "http client request")
+	if _, ok := e.AttrValue("query"); ok {
+		t.Errorf("クエリが無いのに query が出力された: %v", e.Attrs)
+	}
+}
+
+// roundTripFunc は関数を http.RoundTripper として使うための型です。
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestTransportError(t *testing.T) {
+	errDial := errors.New("dial failed")
+	rec := NewRecorder()
+	tr := NewTransport(roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errDial
+	}), TransportOptions{Logger: slog.New(rec)})
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.invalid/x", nil)
+	if _, err := tr.RoundTrip(req); !errors.Is(err, errDial) {
+		t.Errorf("RoundTrip() error = %v, want %v", err, errDial)
+	}
+	e := rec.RequireLogged(t, slog.LevelError, "http client request", slog.String("host", "example.invalid"))
+	if _, ok := e.AttrValue("status"); ok {
+		t.Errorf("レスポンスが無いのに status が出力された: %v", e.Attrs)
+	}
+}
+
+func TestTransportCustomLevel(t *testing.T) {
+	rec := NewRecorder()
+	tr := NewTransport(roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, ContentLength: -1, Header: http.Header{}}, nil
+	}), TransportOptions{
+		Logger: slog.New(rec),
+		Level:  func(int, error) slog.Level { return slog.LevelDebug },
+	})
+
+	if _, err := tr.RoundTrip(httptest.NewRequest(http.MethodGet, "http://example.com/", nil)); err != nil {
+		t.Fatal(err)
+	}
+	e := rec.RequireLogged(t, slog.LevelDebug, "http client request")
+	if _, ok := e.AttrValue("response_bytes"); ok {
+		t.Error("長さが不明なレスポンスで response_bytes が出力された")
+	}
+}
+
+func TestRedactQuery(t *testing.T) {
+	query := url.Values{"b": {"1", "2"}, "a": {"x"}}
+	if got, want := redactQuery(query, []string{"a"}), "a=x&b=REDACTED&b=REDACTED"; got != want {
+		t.Errorf("redactQuery() = %q, want %q", got, want)
+	}
+}